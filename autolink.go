@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Tracker links references to an external issue tracker, such as JIRA keys
+type Tracker struct {
+	Pattern *regexp.Regexp
+	// URL is the link template, expanded with the pattern's submatches (e.g., https://jira.example.com/browse/${0})
+	URL string
+}
+
+// parseTracker parses a -tracker value of the form REGEX=URL
+func parseTracker(spec string) (Tracker, error) {
+	pattern, url, found := strings.Cut(spec, "=")
+	if !found || pattern == "" || url == "" {
+		return Tracker{}, fmt.Errorf("must be REGEX=URL: %s", spec)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Tracker{}, err
+	}
+	return Tracker{Pattern: re, URL: url}, nil
+}
+
+// autolinkActivities links issue references in pushes' commit messages and in other items' content,
+// relative to each activity's repository
+func autolinkActivities(activities []Activity, username, host string, opts Options) {
+	if !opts.Autolink && len(opts.Trackers) == 0 {
+		return
+	}
+
+	for _, activity := range activities {
+		repo := activityRepo(activity, username)
+		if push := activity.Push; push != nil {
+			for i := range push.Commits {
+				push.Commits[i].Message = autolinkHTML(push.Commits[i].Message, repo, host, opts)
+			}
+			continue
+		}
+
+		item := activity.Item
+		content := item.Content
+		item.Content = autolinkHTML(content, repo, host, opts)
+		if item.Description == content {
+			item.Description = item.Content
+		} else {
+			item.Description = autolinkHTML(item.Description, repo, host, opts)
+		}
+	}
+}
+
+// autolinkHTML links issue references and external tracker keys in an HTML fragment's text,
+// leaving tags and existing links alone
+func autolinkHTML(fragment, repo, host string, opts Options) string {
+	tagRegex := regexp.MustCompile(`<[^>]*>`)
+
+	var b strings.Builder
+	inLink := 0
+	last := 0
+	for _, loc := range tagRegex.FindAllStringIndex(fragment, -1) {
+		text := fragment[last:loc[0]]
+		if inLink > 0 {
+			b.WriteString(text)
+		} else {
+			b.WriteString(autolinkText(text, repo, host, opts))
+		}
+
+		tag := strings.ToLower(fragment[loc[0]:loc[1]])
+		if strings.HasPrefix(tag, "<a ") || tag == "<a>" {
+			inLink++
+		} else if tag == "</a>" && inLink > 0 {
+			inLink--
+		}
+		b.WriteString(fragment[loc[0]:loc[1]])
+		last = loc[1]
+	}
+
+	if inLink > 0 {
+		b.WriteString(fragment[last:])
+	} else {
+		b.WriteString(autolinkText(fragment[last:], repo, host, opts))
+	}
+	return b.String()
+}
+
+// autolinkText links references in a run of (HTML-escaped) text, taking the earliest match of any pattern
+// each time so links never overlap
+func autolinkText(text, repo, host string, opts Options) string {
+	issueRegex := regexp.MustCompile(`(?:([\w.-]+/[\w.-]+))?#(\d+)\b`)
+
+	var b strings.Builder
+	for text != "" {
+		start, end, link := -1, -1, ""
+
+		if opts.Autolink && host != "" {
+			for _, loc := range issueRegex.FindAllStringSubmatchIndex(text, -1) {
+				// Skip HTML entities (&#39;) and references embedded in words or paths
+				if loc[0] > 0 && (text[loc[0]-1] == '&' || text[loc[0]-1] == '/' || isWordChar(text[loc[0]-1])) {
+					continue
+				}
+				issueRepo := repo
+				if loc[2] >= 0 {
+					issueRepo = text[loc[2]:loc[3]]
+				}
+				if !strings.Contains(issueRepo, "/") {
+					continue
+				}
+				start, end = loc[0], loc[1]
+				link = issueURL(opts.Provider, host, issueRepo, text[loc[4]:loc[5]])
+				break
+			}
+		}
+
+		for _, tracker := range opts.Trackers {
+			loc := tracker.Pattern.FindStringSubmatchIndex(text)
+			if loc == nil || loc[0] == loc[1] || (start >= 0 && loc[0] >= start) {
+				continue
+			}
+			start, end = loc[0], loc[1]
+			link = string(tracker.Pattern.ExpandString(nil, tracker.URL, text, loc))
+		}
+
+		if start < 0 {
+			b.WriteString(text)
+			break
+		}
+		b.WriteString(text[:start])
+		fmt.Fprintf(&b, "<a href='%s'>%s</a>", link, text[start:end])
+		text = text[end:]
+	}
+	return b.String()
+}
+
+// isWordChar determines whether c is an ASCII letter, digit, or underscore
+func isWordChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// issueURL returns the provider's web URL for an issue in a repository (GitHub redirects to pull requests)
+func issueURL(provider, host, repo, number string) string {
+	if provider == providerGitLab {
+		return fmt.Sprintf("%s/%s/-/issues/%s", hostURL(host), repo, number)
+	}
+	return fmt.Sprintf("%s/%s/issues/%s", hostURL(host), repo, number)
+}
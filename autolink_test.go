@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestAutolinkHTML(t *testing.T) {
+	jira, err := parseTracker(`\b[A-Z][A-Z0-9]+-\d+\b=https://jira.example.com/browse/${0}`)
+	if err != nil {
+		t.Fatalf("parseTracker() error = %v", err)
+	}
+	opts := Options{Autolink: true, Trackers: []Tracker{jira}}
+
+	tests := []struct {
+		name     string
+		input    string
+		opts     Options
+		expected string
+	}{
+		{
+			name:     "Issue in the activity's repository",
+			input:    "Fix crash on empty feeds (#123)",
+			opts:     opts,
+			expected: "Fix crash on empty feeds (<a href='https://github.com/cdzombak/ghfeed/issues/123'>#123</a>)",
+		},
+		{
+			name:     "Issue in another repository",
+			input:    "Port fix from mmcdole/gofeed#45",
+			opts:     opts,
+			expected: "Port fix from <a href='https://github.com/mmcdole/gofeed/issues/45'>mmcdole/gofeed#45</a>",
+		},
+		{
+			name:     "JIRA key",
+			input:    "OPS-991: rotate keys",
+			opts:     opts,
+			expected: "<a href='https://jira.example.com/browse/OPS-991'>OPS-991</a>: rotate keys",
+		},
+		{
+			name:     "Mixed references",
+			input:    "OPS-991 closes #7",
+			opts:     opts,
+			expected: "<a href='https://jira.example.com/browse/OPS-991'>OPS-991</a> closes <a href='https://github.com/cdzombak/ghfeed/issues/7'>#7</a>",
+		},
+		{
+			name:     "Existing links and entities are left alone",
+			input:    "<a href='https://github.com/cdzombak/ghfeed/pull/264'>View PR <tt>#264</tt></a> it&#39;s done",
+			opts:     opts,
+			expected: "<a href='https://github.com/cdzombak/ghfeed/pull/264'>View PR <tt>#264</tt></a> it&#39;s done",
+		},
+		{
+			name:     "References inside words are left alone",
+			input:    "See abc#12 and https://example.com/page#3",
+			opts:     opts,
+			expected: "See abc#12 and https://example.com/page#3",
+		},
+		{
+			name:     "Issue references not enabled",
+			input:    "OPS-991 closes #7",
+			opts:     Options{Trackers: []Tracker{jira}},
+			expected: "<a href='https://jira.example.com/browse/OPS-991'>OPS-991</a> closes #7",
+		},
+		{
+			name:     "GitLab",
+			input:    "Closes #7",
+			opts:     Options{Autolink: true, Provider: providerGitLab},
+			expected: "Closes <a href='https://github.com/cdzombak/ghfeed/-/issues/7'>#7</a>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := autolinkHTML(tt.input, "cdzombak/ghfeed", "github.com", tt.opts)
+			if result != tt.expected {
+				t.Errorf("autolinkHTML() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTracker(t *testing.T) {
+	for _, spec := range []string{"", "OPS-\\d+", "=https://jira.example.com", "OPS-(=https://jira.example.com"} {
+		if _, err := parseTracker(spec); err == nil {
+			t.Errorf("parseTracker(%q) error = nil, want error", spec)
+		}
+	}
+}
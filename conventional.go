@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ConventionalCommit is a commit subject parsed per Conventional Commits (e.g., "feat(api)!: drop v1")
+type ConventionalCommit struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+// conventionalTypeOrder orders commit types in summaries; other types follow alphabetically
+var conventionalTypeOrder = []string{"feat", "fix", "perf", "refactor", "docs", "test", "build", "ci", "chore", "style", "revert"}
+
+// conventionalGroups are the headings commits are grouped under, by type; other types are grouped under "Other"
+var conventionalGroups = []struct {
+	Heading string
+	Type    string
+}{
+	{"Features", "feat"},
+	{"Fixes", "fix"},
+}
+
+// parseConventionalCommit parses a commit message's subject, reporting whether it follows Conventional Commits
+func parseConventionalCommit(message string) (ConventionalCommit, bool) {
+	subjectRegex := regexp.MustCompile(`^([A-Za-z]+)(?:\(([^)]*)\))?(!)?:\s+(\S.*)$`)
+	matches := subjectRegex.FindStringSubmatch(strings.TrimSpace(message))
+	if matches == nil {
+		return ConventionalCommit{}, false
+	}
+
+	return ConventionalCommit{
+		Type:        strings.ToLower(matches[1]),
+		Scope:       matches[2],
+		Breaking:    matches[3] == "!",
+		Description: matches[4],
+	}, true
+}
+
+// conventionalSummary summarizes the types of commits, e.g. "3 feat, 5 fix", or "" if none follow
+// Conventional Commits
+func conventionalSummary(commits []Commit) string {
+	counts := make(map[string]int)
+	for _, commit := range commits {
+		if cc, ok := parseConventionalCommit(commit.Message); ok {
+			counts[cc.Type]++
+		}
+	}
+
+	var types []string
+	for commitType := range counts {
+		types = append(types, commitType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		rankI, rankJ := conventionalTypeRank(types[i]), conventionalTypeRank(types[j])
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		return types[i] < types[j]
+	})
+
+	var parts []string
+	for _, commitType := range types {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[commitType], commitType))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// conventionalTypeRank returns a type's position in conventionalTypeOrder, placing unknown types last
+func conventionalTypeRank(commitType string) int {
+	for i, t := range conventionalTypeOrder {
+		if t == commitType {
+			return i
+		}
+	}
+	return len(conventionalTypeOrder)
+}
+
+// commitListHTML renders a push's commits, grouped under Features, Fixes, and Other headings when
+// conventional is set and any commit follows Conventional Commits
+func commitListHTML(commits []Commit, conventional bool) []string {
+	if !conventional || conventionalSummary(commits) == "" {
+		var htmlParts []string
+		for _, commit := range commits {
+			htmlParts = append(htmlParts, commitHTML(commit, commit.Message))
+		}
+		return htmlParts
+	}
+
+	groups := make(map[string][]string)
+	for _, commit := range commits {
+		heading := "Other"
+		message := commit.Message
+		if cc, ok := parseConventionalCommit(commit.Message); ok {
+			for _, group := range conventionalGroups {
+				if cc.Type == group.Type {
+					heading = group.Heading
+				}
+			}
+			if heading != "Other" {
+				// The heading already says the type
+				message = cc.Description
+				if cc.Scope != "" {
+					message = fmt.Sprintf("<b>%s:</b> %s", cc.Scope, message)
+				}
+			}
+			if cc.Breaking {
+				message = "<b>BREAKING:</b> " + message
+			}
+		}
+		groups[heading] = append(groups[heading], commitHTML(commit, message))
+	}
+
+	var htmlParts []string
+	for _, heading := range []string{"Features", "Fixes", "Other"} {
+		if len(groups[heading]) == 0 {
+			continue
+		}
+		htmlParts = append(htmlParts, fmt.Sprintf("<h4 style='margin: 12px 0 8px;'>%s</h4>", heading))
+		htmlParts = append(htmlParts, groups[heading]...)
+	}
+	return htmlParts
+}
+
+// commitHTML renders one commit in a push's commit list, leaving the hash unlinked when it has no link
+func commitHTML(commit Commit, message string) string {
+	if commit.Link == "" {
+		return fmt.Sprintf("<div style='margin-bottom: 12px;'><tt>%s</tt>: %s</div>", commit.Hash, message)
+	}
+	return fmt.Sprintf(
+		"<div style='margin-bottom: 12px;'>"+
+			"<tt><a href='%s'>%s</a></tt>: %s"+
+			"</div>",
+		commit.Link,
+		commit.Hash,
+		message,
+	)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected ConventionalCommit
+		ok       bool
+	}{
+		{"feat: add GitLab provider", ConventionalCommit{Type: "feat", Description: "add GitLab provider"}, true},
+		{"fix(api): handle empty feeds", ConventionalCommit{Type: "fix", Scope: "api", Description: "handle empty feeds"}, true},
+		{"feat(cli)!: drop -legacy flag", ConventionalCommit{Type: "feat", Scope: "cli", Breaking: true, Description: "drop -legacy flag"}, true},
+		{"Chore: bump deps", ConventionalCommit{Type: "chore", Description: "bump deps"}, true},
+		{"Fix the build", ConventionalCommit{}, false},
+		{"Merge branch 'main': conflicts", ConventionalCommit{}, false},
+		{"feat:missing space", ConventionalCommit{}, false},
+	}
+
+	for _, tt := range tests {
+		result, ok := parseConventionalCommit(tt.message)
+		if ok != tt.ok || result != tt.expected {
+			t.Errorf("parseConventionalCommit(%q) = %+v, %v, want %+v, %v", tt.message, result, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestConventionalCommitsItem(t *testing.T) {
+	latestTime := time.Date(2025, 9, 15, 1, 28, 2, 0, time.UTC)
+	activity := &BranchActivity{
+		Repo:   "ghfeed",
+		Branch: "main",
+		Commits: []Commit{
+			{Hash: "eeeeeee", Message: "chore: tidy", Link: "https://github.com/cdzombak/ghfeed/commit/eeeeeee"},
+			{Hash: "ddddddd", Message: "fix(api): handle empty feeds", Link: "https://github.com/cdzombak/ghfeed/commit/ddddddd"},
+			{Hash: "ccccccc", Message: "feat(cli)!: drop -legacy flag", Link: "https://github.com/cdzombak/ghfeed/commit/ccccccc"},
+			{Hash: "bbbbbbb", Message: "Fix the build", Link: "https://github.com/cdzombak/ghfeed/commit/bbbbbbb"},
+			{Hash: "aaaaaaa", Message: "fix: typo", Link: "https://github.com/cdzombak/ghfeed/commit/aaaaaaa"},
+		},
+		LatestTime:  &latestTime,
+		CompareLink: "https://github.com/cdzombak/ghfeed/compare/aaaaaaa^...eeeeeee",
+	}
+
+	result := createConsolidatedBranchItem(activity, "cdzombak", true)
+
+	expectedTitle := "cdzombak pushed 5 commits to ghfeed/main (1 feat, 2 fix, 1 chore)"
+	if result.Title != expectedTitle {
+		t.Errorf("createConsolidatedBranchItem().Title = %v, want %v", result.Title, expectedTitle)
+	}
+
+	// Headings appear in order, each followed by its commits
+	expectedOrder := []string{
+		"<h4 style='margin: 12px 0 8px;'>Features</h4>",
+		"<tt><a href='https://github.com/cdzombak/ghfeed/commit/ccccccc'>ccccccc</a></tt>: <b>BREAKING:</b> <b>cli:</b> drop -legacy flag",
+		"<h4 style='margin: 12px 0 8px;'>Fixes</h4>",
+		"<b>api:</b> handle empty feeds",
+		"</tt>: typo",
+		"<h4 style='margin: 12px 0 8px;'>Other</h4>",
+		"</tt>: chore: tidy",
+		"</tt>: Fix the build",
+	}
+	content := result.Content
+	for _, expected := range expectedOrder {
+		i := strings.Index(content, expected)
+		if i < 0 {
+			t.Fatalf("createConsolidatedBranchItem().Content missing %q in order, got %v", expected, result.Content)
+		}
+		content = content[i+len(expected):]
+	}
+
+	// Without the option, commits are listed as before
+	plain := createConsolidatedBranchItem(activity, "cdzombak", false)
+	if plain.Title != "cdzombak pushed 5 commits to ghfeed/main" || strings.Contains(plain.Content, "<h4") {
+		t.Errorf("createConsolidatedBranchItem() without conventional commits = %v, %v", plain.Title, plain.Content)
+	}
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// defaultJitter is the fraction of each job's interval added at random to its delays when the config
+// doesn't set one, so jobs started together drift apart
+const defaultJitter = 0.1
+
+// Clock is the daemon's source of time, replaced with a fake in tests
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the system clock
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Duration is a time.Duration read from JSON as a string like "15m"
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"15m\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// DaemonConfig is the daemon's list of feed jobs, read from a JSON file
+type DaemonConfig struct {
+	// Jitter is the fraction of each job's interval added at random to its delays; defaults to defaultJitter
+	Jitter *float64 `json:"jitter"`
+	Jobs   []Job    `json:"jobs"`
+}
+
+// Job is one feed the daemon fetches, consolidates, and writes on its own interval
+type Job struct {
+	Name   string `json:"name"`
+	Source string `json:"source"` // atom (default), events-api, or git
+	URL    string `json:"url"`
+	// Output is the file the rendered feed is written to, atomically
+	Output   string   `json:"output"`
+	Format   string   `json:"format"` // atom (default), rss, or json
+	Interval Duration `json:"interval"`
+
+	Title             string   `json:"title"`
+	ConsolidatePushes *bool    `json:"consolidate_pushes"` // defaults to true
+	PushWindow        Duration `json:"push_window"`
+	Provider          string   `json:"provider"`
+	GitHubHost        string   `json:"github_host"`
+
+	// Webhook, when set, receives new items; WebhookState records what it has been sent
+	Webhook      string `json:"webhook"`
+	WebhookType  string `json:"webhook_type"` // slack (default), discord, or mattermost
+	WebhookState string `json:"webhook_state"`
+}
+
+// loadDaemonConfig reads and validates the daemon's config file, filling in defaults
+func loadDaemonConfig(path string) (*DaemonConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config DaemonConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	if len(config.Jobs) == 0 {
+		return nil, fmt.Errorf("%s has no jobs", path)
+	}
+	if config.Jitter == nil {
+		jitter := defaultJitter
+		config.Jitter = &jitter
+	} else if *config.Jitter < 0 || *config.Jitter > 1 {
+		return nil, fmt.Errorf("jitter must be between 0 and 1")
+	}
+
+	names := make(map[string]bool)
+	outputs := make(map[string]bool)
+	for i := range config.Jobs {
+		job := &config.Jobs[i]
+		if job.Name == "" {
+			return nil, fmt.Errorf("job %d has no name", i+1)
+		}
+		if job.URL == "" || job.Output == "" {
+			return nil, fmt.Errorf("job %s: url and output are required", job.Name)
+		}
+		if job.Interval <= 0 {
+			return nil, fmt.Errorf("job %s: interval is required", job.Name)
+		}
+		// Two jobs writing one output would race, so they're rejected rather than serialized
+		if names[job.Name] || outputs[job.Output] {
+			return nil, fmt.Errorf("job %s: duplicate name or output", job.Name)
+		}
+		names[job.Name] = true
+		outputs[job.Output] = true
+
+		if job.Source == "" {
+			job.Source = "atom"
+		}
+		if job.Source != "atom" && job.Source != "events-api" && job.Source != "git" {
+			return nil, fmt.Errorf("job %s: source must be 'atom', 'events-api', or 'git'", job.Name)
+		}
+		if job.Format == "" {
+			job.Format = "atom"
+		}
+		if job.Format != "atom" && job.Format != "rss" && job.Format != "json" {
+			return nil, fmt.Errorf("job %s: format must be 'atom', 'rss', or 'json'", job.Name)
+		}
+		if job.Provider = normalizeProvider(job.Provider); job.Provider == "" {
+			return nil, fmt.Errorf("job %s: provider must be 'github', 'gitea', 'forgejo', or 'gitlab'", job.Name)
+		}
+		if job.Source == "events-api" && job.Provider != providerGitHub {
+			return nil, fmt.Errorf("job %s: source events-api is only supported with provider github", job.Name)
+		}
+		if job.Webhook != "" {
+			if job.WebhookType == "" {
+				job.WebhookType = webhookSlack
+			}
+			if job.WebhookType != webhookSlack && job.WebhookType != webhookDiscord && job.WebhookType != webhookMattermost {
+				return nil, fmt.Errorf("job %s: webhook_type must be 'slack', 'discord', or 'mattermost'", job.Name)
+			}
+			if job.WebhookState == "" {
+				return nil, fmt.Errorf("job %s: webhook requires webhook_state", job.Name)
+			}
+			if outputs[job.WebhookState] {
+				return nil, fmt.Errorf("job %s: webhook_state is another job's output or webhook_state", job.Name)
+			}
+			outputs[job.WebhookState] = true
+		}
+		if job.GitHubHost != "" {
+			if job.GitHubHost = normalizeHost(job.GitHubHost); job.GitHubHost == "" {
+				return nil, fmt.Errorf("job %s: github_host must be a hostname", job.Name)
+			}
+		}
+	}
+
+	return &config, nil
+}
+
+// Daemon runs feed jobs, each on its own interval
+type Daemon struct {
+	Jobs    []Job
+	Fetcher *Fetcher
+	Clock   Clock
+	// Jitter is the fraction of each job's interval added at random to its delays
+	Jitter float64
+	// Random returns a number in [0, 1) for jitter
+	Random func() float64
+}
+
+// Run runs every job until ctx is canceled, then waits for runs in progress to finish.
+// Each job runs in its own goroutine, one run at a time, so a slow run delays that job's next run
+// rather than overlapping it.
+func (d *Daemon) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range d.Jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.runJob(ctx, job)
+		}()
+	}
+	wg.Wait()
+}
+
+// runJob runs job after a random initial delay and then every interval (plus jitter) until ctx is canceled
+func (d *Daemon) runJob(ctx context.Context, job Job) {
+	delay := d.jitter(job)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.Clock.After(delay):
+		}
+
+		started := d.Clock.Now()
+		if err := d.runOnce(job); err != nil {
+			metrics.JobRuns.Inc(job.Name, "failure")
+			logger.Error("job failed", "job", job.Name, "error", err)
+		} else {
+			metrics.JobRuns.Inc(job.Name, "success")
+			metrics.JobLastSuccess.Set(float64(d.Clock.Now().Unix()), job.Name)
+			logger.Info("job succeeded", "job", job.Name, "output", job.Output, "duration", d.Clock.Now().Sub(started))
+		}
+
+		delay = time.Duration(job.Interval) + d.jitter(job)
+	}
+}
+
+// jitter returns a random delay of up to the daemon's jitter fraction of the job's interval
+func (d *Daemon) jitter(job Job) time.Duration {
+	if d.Jitter <= 0 {
+		return 0
+	}
+	return time.Duration(d.Random() * d.Jitter * float64(job.Interval))
+}
+
+// runOnce fetches and consolidates the job's feed and writes it to the job's output. On failure, the
+// previous output is left in place.
+func (d *Daemon) runOnce(job Job) error {
+	consolidatePushes := true
+	if job.ConsolidatePushes != nil {
+		consolidatePushes = *job.ConsolidatePushes
+	}
+
+	var pushes PushIndex
+	if job.Webhook != "" {
+		pushes = PushIndex{}
+	}
+
+	feed, err := fetchConsolidatedFeed(d.Fetcher, job.Source, job.URL, Options{
+		Title:             job.Title,
+		ConsolidatePushes: consolidatePushes,
+		Host:              job.GitHubHost,
+		PushWindow:        time.Duration(job.PushWindow),
+		Provider:          job.Provider,
+		Pushes:            pushes,
+	})
+	if err != nil {
+		return fmt.Errorf("fetching feed: %w", err)
+	}
+
+	err = writeFileAtomic(job.Output, func(w io.Writer) error {
+		return renderFeed(w, feed, job.Format)
+	})
+	if err != nil || job.Webhook == "" {
+		return err
+	}
+
+	webhook := &Webhook{URL: job.Webhook, Kind: job.WebhookType, StatePath: job.WebhookState}
+	if err := webhook.Notify(feed, pushes, d.Clock.Now()); err != nil {
+		return fmt.Errorf("notifying webhook: %w", err)
+	}
+	return nil
+}
+
+// runDaemon implements the daemon subcommand: ghfeed daemon [options] <config.json>
+func runDaemon(args []string) {
+	var configPath string
+	var tokenFile string
+	var fetchTimeout = defaultFetchTimeout
+	var fetchRetries = defaultFetchRetries
+	var metricsAddr string
+	var logLevel = slog.LevelInfo
+	var logFormat = "text"
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "-token-file" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -token-file flag requires a path argument\n")
+				os.Exit(1)
+			}
+			tokenFile = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-timeout" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -timeout flag requires a duration argument (e.g. 30s)\n")
+				os.Exit(1)
+			}
+			timeout, err := time.ParseDuration(args[i+1])
+			if err != nil || timeout < 0 {
+				fmt.Fprintf(os.Stderr, "Error: -timeout must be a duration like 30s or 2m\n")
+				os.Exit(1)
+			}
+			fetchTimeout = timeout
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-retries" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -retries flag requires a number argument\n")
+				os.Exit(1)
+			}
+			retries, err := strconv.Atoi(args[i+1])
+			if err != nil || retries < 0 {
+				fmt.Fprintf(os.Stderr, "Error: -retries must be a non-negative integer\n")
+				os.Exit(1)
+			}
+			fetchRetries = retries
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-metrics-addr" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -metrics-addr flag requires an address argument (e.g. :9090)\n")
+				os.Exit(1)
+			}
+			metricsAddr = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-verbose" {
+			logLevel = slog.LevelDebug
+		} else if arg == "-log-level" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -log-level flag requires a level argument (debug, info, warn, or error)\n")
+				os.Exit(1)
+			}
+			level, err := parseLogLevel(args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: -log-level %v\n", err)
+				os.Exit(1)
+			}
+			logLevel = level
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-log-format" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -log-format flag requires a format argument (text or json)\n")
+				os.Exit(1)
+			}
+			if args[i+1] != "text" && args[i+1] != "json" {
+				fmt.Fprintf(os.Stderr, "Error: -log-format must be 'text' or 'json'\n")
+				os.Exit(1)
+			}
+			logFormat = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if configPath == "" {
+			configPath = arg
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: unexpected argument: %s\n", arg)
+			os.Exit(1)
+		}
+	}
+
+	if configPath == "" {
+		fmt.Fprintf(os.Stderr, "Usage: %s daemon [-token-file <path>] [-timeout <duration>] [-retries <n>] [-metrics-addr <addr>] [-verbose] [-log-level <level>] [-log-format <format>] <config.json>\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	logger = newLogger(os.Stderr, logLevel, logFormat)
+
+	config, err := loadDaemonConfig(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading daemon config: %v\n", err)
+		os.Exit(1)
+	}
+
+	token, err := loadToken(tokenFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading token: %v\n", err)
+		os.Exit(1)
+	}
+	fetcher := newFetcher(token)
+	fetcher.Timeout = fetchTimeout
+	fetcher.MaxRetries = fetchRetries
+
+	// Stop scheduling on SIGTERM or SIGINT, letting runs in progress finish
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	if metricsAddr != "" {
+		listener, err := net.Listen("tcp", metricsAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error serving metrics: %v\n", err)
+			os.Exit(1)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics)
+		server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go server.Serve(listener)
+		defer server.Close()
+		logger.Info("serving metrics", "url", fmt.Sprintf("http://%s/metrics", listener.Addr()))
+	}
+
+	daemon := &Daemon{
+		Jobs:    config.Jobs,
+		Fetcher: fetcher,
+		Clock:   realClock{},
+		Jitter:  *config.Jitter,
+		Random:  rand.Float64,
+	}
+	logger.Info("daemon running", "jobs", len(config.Jobs))
+	daemon.Run(ctx)
+	logger.Info("daemon stopped")
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only moves when Advance is called
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward, firing timers that come due
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// BlockUntil waits until n timers are pending, i.e. every job is idle and waiting for its next run
+func (c *fakeClock) BlockUntil(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		pending := len(c.waiters)
+		c.mu.Unlock()
+		if pending >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d pending timers", n)
+}
+
+func TestDaemon(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>cdzombak's Activity</title>
+  <link type="text/html" rel="alternate" href="https://github.com/cdzombak"/>
+  <entry>
+    <id>tag:github.com,2008:WatchEvent/1</id>
+    <published>2025-09-14T22:58:34Z</published>
+    <link type="text/html" rel="alternate" href="https://github.com/golang/go"/>
+    <title type="html">cdzombak starred golang/go</title>
+  </entry>
+</feed>`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "feed.json")
+	clock := newFakeClock()
+	daemon := &Daemon{
+		Jobs: []Job{
+			{Name: "cdzombak", Source: "atom", URL: server.URL, Output: output, Format: "json", Interval: Duration(time.Hour), Provider: providerGitHub},
+		},
+		Fetcher: newTestFetcher(),
+		Clock:   clock,
+		Jitter:  0.5,
+		Random:  func() float64 { return 0.5 },
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		daemon.Run(ctx)
+		close(done)
+	}()
+
+	// The first run waits for the initial jitter (0.5 * 0.5 * 1h = 15m)
+	clock.BlockUntil(t, 1)
+	if requests.Load() != 0 {
+		t.Fatalf("requests before initial jitter = %d, want 0", requests.Load())
+	}
+	clock.Advance(15 * time.Minute)
+
+	// Once it has run, the job waits for its interval plus jitter
+	clock.BlockUntil(t, 1)
+	if requests.Load() != 1 {
+		t.Fatalf("requests after first run = %d, want 1", requests.Load())
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	if !strings.Contains(string(data), "cdzombak starred golang/go") {
+		t.Errorf("output = %s, want rendered feed", data)
+	}
+
+	clock.Advance(time.Hour)
+	time.Sleep(10 * time.Millisecond)
+	if requests.Load() != 1 {
+		t.Errorf("requests before interval and jitter elapsed = %d, want 1", requests.Load())
+	}
+	clock.Advance(15 * time.Minute)
+	clock.BlockUntil(t, 1)
+	if requests.Load() != 2 {
+		t.Errorf("requests after second interval = %d, want 2", requests.Load())
+	}
+
+	// Shutting down stops the job while it waits
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("daemon didn't stop after cancel")
+	}
+
+	// Only the output itself is left behind
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("output directory has %d entries, want 1", len(entries))
+	}
+}
+
+func TestDaemonFailureKeepsOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	output := filepath.Join(t.TempDir(), "feed.atom")
+	if err := os.WriteFile(output, []byte("previous"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	daemon := &Daemon{Fetcher: newTestFetcher()}
+	err := daemon.runOnce(Job{Name: "down", Source: "atom", URL: server.URL, Output: output, Format: "atom"})
+	if err == nil {
+		t.Fatal("runOnce() error = nil, want fetch error")
+	}
+
+	data, _ := os.ReadFile(output)
+	if string(data) != "previous" {
+		t.Errorf("output after failed run = %q, want previous output", data)
+	}
+}
+
+func TestLoadDaemonConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{
+			name:   "Valid",
+			config: `{"jobs": [{"name": "a", "url": "https://github.com/a.atom", "output": "/tmp/a.atom", "interval": "15m"}]}`,
+		},
+		{
+			name:    "Missing interval",
+			config:  `{"jobs": [{"name": "a", "url": "https://github.com/a.atom", "output": "/tmp/a.atom"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Bad interval",
+			config:  `{"jobs": [{"name": "a", "url": "https://github.com/a.atom", "output": "/tmp/a.atom", "interval": "often"}]}`,
+			wantErr: true,
+		},
+		{
+			name: "Duplicate output",
+			config: `{"jobs": [
+				{"name": "a", "url": "https://github.com/a.atom", "output": "/tmp/a.atom", "interval": "15m"},
+				{"name": "b", "url": "https://github.com/b.atom", "output": "/tmp/a.atom", "interval": "15m"}
+			]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Bad jitter",
+			config:  `{"jitter": 2, "jobs": [{"name": "a", "url": "https://github.com/a.atom", "output": "/tmp/a.atom", "interval": "15m"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Webhook without state",
+			config:  `{"jobs": [{"name": "a", "url": "https://github.com/a.atom", "output": "/tmp/a.atom", "interval": "15m", "webhook": "https://hooks.slack.com/services/x"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Bad webhook type",
+			config:  `{"jobs": [{"name": "a", "url": "https://github.com/a.atom", "output": "/tmp/a.atom", "interval": "15m", "webhook": "https://example.com/hook", "webhook_type": "teams", "webhook_state": "/tmp/a.json"}]}`,
+			wantErr: true,
+		},
+		{
+			name: "Shared webhook state",
+			config: `{"jobs": [
+				{"name": "a", "url": "https://github.com/a.atom", "output": "/tmp/a.atom", "interval": "15m", "webhook": "https://hooks.slack.com/services/x", "webhook_state": "/tmp/a.json"},
+				{"name": "b", "url": "https://github.com/b.atom", "output": "/tmp/b.atom", "interval": "15m", "webhook": "https://hooks.slack.com/services/x", "webhook_state": "/tmp/a.json"}
+			]}`,
+			wantErr: true,
+		},
+		{
+			name:    "No jobs",
+			config:  `{"jobs": []}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := loadDaemonConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadDaemonConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			job := config.Jobs[0]
+			if job.Source != "atom" || job.Format != "atom" || job.Provider != providerGitHub || *config.Jitter != defaultJitter {
+				t.Errorf("loadDaemonConfig() defaults = %+v, jitter %v", job, *config.Jitter)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Digest periods, for -digest
+const (
+	digestDaily  = "daily"
+	digestWeekly = "weekly"
+)
+
+// Sections for items that aren't in a single repository
+const (
+	digestMultiRepo = "Multiple repositories"
+	digestOtherRepo = "Other activity"
+)
+
+// digestPeriod is one day or week of a digest
+type digestPeriod struct {
+	Start time.Time
+	GUID  string
+	Label string
+	Items []*gofeed.Item
+}
+
+// periodStart returns the start of the day or week (starting Monday) containing t, in loc
+func periodStart(t time.Time, period string, loc *time.Location) time.Time {
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	if period == digestWeekly {
+		daysSinceMonday := (int(start.Weekday()) + 6) % 7
+		start = start.AddDate(0, 0, -daysSinceMonday)
+	}
+	return start
+}
+
+// newDigestPeriod returns the period starting at start, with a GUID that stays the same as items are added
+func newDigestPeriod(start time.Time, period string) *digestPeriod {
+	if period == digestWeekly {
+		year, week := start.ISOWeek()
+		return &digestPeriod{
+			Start: start,
+			GUID:  fmt.Sprintf("digest-weekly-%04d-W%02d", year, week),
+			Label: "the week of " + start.Format("January 2, 2006"),
+		}
+	}
+	return &digestPeriod{
+		Start: start,
+		GUID:  "digest-daily-" + start.Format("2006-01-02"),
+		Label: start.Format("Monday, January 2, 2006"),
+	}
+}
+
+// digestItems rolls items up into one item per period, with a section for each repository and totals.
+// Items without a time are left as they are. It returns the new items, newest first, and the digest GUID
+// each rolled-up item's GUID now belongs to.
+func digestItems(items []*gofeed.Item, pushes PushIndex, username, period string, loc *time.Location) ([]*gofeed.Item, map[string]string) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	periods := make(map[time.Time]*digestPeriod)
+	var undated []*gofeed.Item
+	for _, item := range items {
+		published := itemTime(item)
+		if published.IsZero() {
+			undated = append(undated, item)
+			continue
+		}
+		start := periodStart(published, period, loc)
+		if periods[start] == nil {
+			periods[start] = newDigestPeriod(start, period)
+		}
+		periods[start].Items = append(periods[start].Items, item)
+	}
+
+	var digests []*gofeed.Item
+	guids := make(map[string]string)
+	for _, p := range periods {
+		digests = append(digests, createDigestItem(p, pushes, username))
+		for _, item := range p.Items {
+			guids[item.GUID] = p.GUID
+		}
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return digests[i].PublishedParsed.After(*digests[j].PublishedParsed)
+	})
+
+	return append(digests, undated...), guids
+}
+
+// itemTime returns an item's updated or published time, or the zero time if it has neither
+func itemTime(item *gofeed.Item) time.Time {
+	if item.UpdatedParsed != nil {
+		return *item.UpdatedParsed
+	}
+	if item.PublishedParsed != nil {
+		return *item.PublishedParsed
+	}
+	return time.Time{}
+}
+
+// createDigestItem creates the item for a period, with totals and a section per repository
+func createDigestItem(p *digestPeriod, pushes PushIndex, username string) *gofeed.Item {
+	sections := make(map[string][]*gofeed.Item)
+	touched := make(map[string]bool)
+	var commits, pullRequests, other int
+	var latest time.Time
+	for _, item := range p.Items {
+		repo := digestOtherRepo
+		if itemPushes := pushes[item.GUID]; len(itemPushes) > 0 {
+			repo = pushesRepo(itemPushes, username)
+			if repo == "" {
+				repo = digestMultiRepo
+			}
+			for _, push := range itemPushes {
+				commits += commitCount(push)
+				touched[activityRepo(Activity{Push: push}, username)] = true
+			}
+		} else {
+			if itemRepo := activityRepo(Activity{Item: item}, username); itemRepo != "" {
+				repo = itemRepo
+				touched[repo] = true
+			}
+			if _, ok := parsePullRequestTitle(item.Title); ok {
+				pullRequests++
+			} else {
+				other++
+			}
+		}
+		sections[repo] = append(sections[repo], item)
+		if t := itemTime(item); t.After(latest) {
+			latest = t
+		}
+	}
+
+	var repos []string
+	for repo := range sections {
+		if repo != digestMultiRepo && repo != digestOtherRepo {
+			repos = append(repos, repo)
+		}
+	}
+	sort.Strings(repos)
+	for _, repo := range []string{digestMultiRepo, digestOtherRepo} {
+		if sections[repo] != nil {
+			repos = append(repos, repo)
+		}
+	}
+
+	var totals []string
+	if commits > 0 {
+		totals = append(totals, countNoun(commits, "commit", "commits"))
+	}
+	if pullRequests > 0 {
+		totals = append(totals, countNoun(pullRequests, "pull request", "pull requests"))
+	}
+	if other > 0 {
+		totals = append(totals, countNoun(other, "other event", "other events"))
+	}
+	summary := strings.Join(totals, ", ")
+	if len(touched) > 0 {
+		summary += " in " + countNoun(len(touched), "repository", "repositories")
+	}
+
+	var htmlParts []string
+	htmlParts = append(htmlParts, "<div>")
+	htmlParts = append(htmlParts, fmt.Sprintf("<p>%s</p>", html.EscapeString(summary)))
+	for _, repo := range repos {
+		htmlParts = append(htmlParts, fmt.Sprintf("<h3 style='margin: 16px 0 8px;'>%s</h3>", html.EscapeString(repo)))
+		for _, item := range sections[repo] {
+			title := html.EscapeString(item.Title)
+			if item.Link != "" {
+				title = fmt.Sprintf("<a href='%s'>%s</a>", item.Link, title)
+			}
+			htmlParts = append(htmlParts, fmt.Sprintf(
+				"<div style='margin-bottom: 12px;'>"+
+					"<div><b>%s</b></div>"+
+					"%s"+
+					"</div>",
+				title,
+				itemBody(item),
+			))
+		}
+	}
+	htmlParts = append(htmlParts, "</div>")
+	htmlContent := strings.Join(htmlParts, "")
+
+	return &gofeed.Item{
+		Title:           fmt.Sprintf("Activity for %s: %s", p.Label, summary),
+		Description:     htmlContent,
+		Content:         htmlContent,
+		Published:       latest.Format(time.RFC3339),
+		PublishedParsed: &latest,
+		Updated:         latest.Format(time.RFC3339),
+		UpdatedParsed:   &latest,
+		GUID:            p.GUID,
+	}
+}
+
+// countNoun formats a count with the singular or plural noun, like "1 commit" or "3 commits"
+func countNoun(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
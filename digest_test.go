@@ -0,0 +1,152 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// digestTestFeed is explainTestFeed with a fork two days later
+func digestTestFeed() *gofeed.Feed {
+	feed := explainTestFeed()
+	forked := time.Date(2025, 9, 17, 9, 0, 0, 0, time.UTC)
+	fork := &gofeed.Item{
+		GUID:            "tag:github.com,2008:ForkEvent/1",
+		Title:           "cdzombak forked mmcdole/gofeed from cdzombak/gofeed",
+		Link:            "https://github.com/cdzombak/gofeed",
+		PublishedParsed: &forked,
+	}
+	feed.Items = append([]*gofeed.Item{fork}, feed.Items...)
+	return feed
+}
+
+func TestDigestDaily(t *testing.T) {
+	explanation := &Explanation{}
+	output := consolidateCommits(digestTestFeed(), Options{
+		ConsolidatePushes: true,
+		Digest:            digestDaily,
+		DigestLocation:    time.UTC,
+		Explain:           explanation,
+	})
+
+	if len(output.Items) != 2 {
+		t.Fatalf("consolidateCommits() returned %d items, want 2 daily digests", len(output.Items))
+	}
+	expected := []struct {
+		guid  string
+		title string
+	}{
+		{"digest-daily-2025-09-17", "Activity for Wednesday, September 17, 2025: 1 other event in 1 repository"},
+		{"digest-daily-2025-09-15", "Activity for Monday, September 15, 2025: 2 commits, 1 pull request, 1 other event in 3 repositories"},
+	}
+	for i, want := range expected {
+		if got := output.Items[i]; got.GUID != want.guid || got.Title != want.title {
+			t.Errorf("digest %d = %q %q, want %q %q", i, got.GUID, got.Title, want.guid, want.title)
+		}
+	}
+
+	monday := output.Items[1]
+	if !monday.PublishedParsed.Equal(time.Date(2025, 9, 15, 3, 0, 0, 0, time.UTC)) {
+		t.Errorf("digest published = %v, want the latest item's time", monday.PublishedParsed)
+	}
+	sections := []string{
+		"<h3 style='margin: 16px 0 8px;'>cdzombak/dotfiles</h3>",
+		"cdzombak pushed 2 commits to dotfiles/main",
+		"<h3 style='margin: 16px 0 8px;'>cdzombak/ghfeed</h3>",
+		"<h3 style='margin: 16px 0 8px;'>mmcdole/gofeed</h3>",
+		"cdzombak opened PR #264 in mmcdole/gofeed",
+	}
+	position := 0
+	for _, section := range sections {
+		next := strings.Index(monday.Content[position:], section)
+		if next == -1 {
+			t.Errorf("digest content doesn't contain %q after position %d:\n%s", section, position, monday.Content)
+			continue
+		}
+		position += next
+	}
+
+	for _, entry := range explanation.Items {
+		if !strings.HasPrefix(entry.OutputGUID, "digest-daily-") {
+			t.Errorf("explanation item %d output = %q, want a digest", entry.Index, entry.OutputGUID)
+		}
+	}
+}
+
+func TestDigestWeekly(t *testing.T) {
+	output := consolidateCommits(digestTestFeed(), Options{
+		ConsolidatePushes: true,
+		Digest:            digestWeekly,
+		DigestLocation:    time.UTC,
+		MaxItems:          1,
+	})
+
+	if len(output.Items) != 1 {
+		t.Fatalf("consolidateCommits() returned %d items, want 1 weekly digest", len(output.Items))
+	}
+	want := "Activity for the week of September 15, 2025: 2 commits, 1 pull request, 2 other events in 4 repositories"
+	if got := output.Items[0]; got.GUID != "digest-weekly-2025-W38" || got.Title != want {
+		t.Errorf("weekly digest = %q %q, want digest-weekly-2025-W38 %q", got.GUID, got.Title, want)
+	}
+}
+
+func TestPeriodStart(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+
+	tests := []struct {
+		t        time.Time
+		period   string
+		loc      *time.Location
+		expected time.Time
+	}{
+		{time.Date(2025, 9, 15, 3, 0, 0, 0, time.UTC), digestDaily, time.UTC, time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)},
+		// 3:00 UTC is still the previous evening in New York
+		{time.Date(2025, 9, 15, 3, 0, 0, 0, time.UTC), digestDaily, newYork, time.Date(2025, 9, 14, 0, 0, 0, 0, newYork)},
+		{time.Date(2025, 9, 21, 23, 0, 0, 0, time.UTC), digestWeekly, time.UTC, time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC), digestWeekly, time.UTC, time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := periodStart(tt.t, tt.period, tt.loc); !got.Equal(tt.expected) {
+			t.Errorf("periodStart(%v, %s, %v) = %v, want %v", tt.t, tt.period, tt.loc, got, tt.expected)
+		}
+	}
+}
+
+func TestDigestUndatedItems(t *testing.T) {
+	published := time.Date(2025, 9, 15, 3, 0, 0, 0, time.UTC)
+	items := []*gofeed.Item{
+		{GUID: "dated", Title: "cdzombak starred cdzombak/ghfeed", Link: "https://github.com/cdzombak/ghfeed", PublishedParsed: &published},
+		{GUID: "undated", Title: "cdzombak did something"},
+	}
+
+	digests, guids := digestItems(items, nil, "cdzombak", digestDaily, time.UTC)
+	if len(digests) != 2 || digests[0].GUID != "digest-daily-2025-09-15" || digests[1].GUID != "undated" {
+		t.Errorf("digestItems() = %v, want a digest and the undated item", digests)
+	}
+	if guids["dated"] != "digest-daily-2025-09-15" || guids["undated"] != "" {
+		t.Errorf("digestItems() GUIDs = %v", guids)
+	}
+}
+
+func TestCountNoun(t *testing.T) {
+	tests := []struct {
+		n        int
+		expected string
+	}{
+		{0, "0 commits"},
+		{1, "1 commit"},
+		{23, "23 commits"},
+	}
+
+	for _, tt := range tests {
+		if got := countNoun(tt.n, "commit", "commits"); got != tt.expected {
+			t.Errorf("countNoun(%d) = %q, want %q", tt.n, got, tt.expected)
+		}
+	}
+}
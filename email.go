@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// smtpPasswordEnvVar is the environment variable the SMTP password is read from when no password file is given
+const smtpPasswordEnvVar = "GHFEED_SMTP_PASSWORD"
+
+// defaultEmailSubject is the subject of emails for feeds without a title
+const defaultEmailSubject = "GitHub activity"
+
+// Email is where -format email delivers the feed: written to stdout as a .eml message, appended to an
+// mbox file, or sent through an SMTP server
+type Email struct {
+	From *mail.Address
+	To   []*mail.Address
+	// MboxPath, when set, is an mbox file the message is appended to
+	MboxPath string
+	// SMTP, when set, sends the message
+	SMTP *Mailer
+}
+
+// Mailer sends messages through an SMTP server, using STARTTLS when the server offers it
+type Mailer struct {
+	Addr     string // host:port; port 465 uses implicit TLS
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+// deliver renders the feed as an email and delivers it
+func (e *Email) deliver(stdout io.Writer, feed *gofeed.Feed, now time.Time) error {
+	var message bytes.Buffer
+	if err := renderEmail(&message, feed, e, now); err != nil {
+		return err
+	}
+
+	switch {
+	case e.SMTP != nil:
+		var to []string
+		for _, address := range e.To {
+			to = append(to, address.Address)
+		}
+		return e.SMTP.send(e.From.Address, to, message.Bytes())
+	case e.MboxPath != "":
+		return appendMbox(e.MboxPath, e.From.Address, now, message.Bytes())
+	default:
+		_, err := stdout.Write(message.Bytes())
+		return err
+	}
+}
+
+// renderEmail writes the feed to w as a multipart/alternative MIME message, with a plain text part and
+// an HTML part made of the items' HTML bodies
+func renderEmail(w io.Writer, feed *gofeed.Feed, email *Email, now time.Time) error {
+	defer metrics.RenderDuration.ObserveDuration(time.Now(), "email")
+
+	subject := feed.Title
+	if subject == "" {
+		subject = defaultEmailSubject
+	}
+	var to []string
+	for _, address := range email.To {
+		to = append(to, address.String())
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", emailText(feed, subject)},
+		{"text/html; charset=utf-8", emailHTML(feed, subject)},
+	} {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		partWriter, err := parts.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		encoder := quotedprintable.NewWriter(partWriter)
+		if _, err := io.WriteString(encoder, part.content); err != nil {
+			return err
+		}
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return err
+	}
+
+	headers := [][2]string{
+		{"From", email.From.String()},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", emailMessageID(feed, email.From, now)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": parts.Boundary()})},
+	}
+	var message bytes.Buffer
+	for _, header := range headers {
+		fmt.Fprintf(&message, "%s: %s\r\n", header[0], header[1])
+	}
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	_, err := w.Write(message.Bytes())
+	return err
+}
+
+// emailMessageID returns a Message-ID for the email, unique to the feed's items and the time it was sent
+func emailMessageID(feed *gofeed.Feed, from *mail.Address, now time.Time) string {
+	hash := sha256.New()
+	for _, item := range feed.Items {
+		io.WriteString(hash, item.GUID)
+	}
+	domain := "ghfeed.invalid"
+	if at := strings.LastIndex(from.Address, "@"); at != -1 {
+		domain = from.Address[at+1:]
+	}
+	return fmt.Sprintf("<ghfeed.%d.%x@%s>", now.Unix(), hash.Sum(nil)[:8], domain)
+}
+
+// emailItemTime formats an item's time for email bodies
+func emailItemTime(item *gofeed.Item) string {
+	if item.PublishedParsed == nil {
+		return ""
+	}
+	return item.PublishedParsed.Format("Mon Jan 2, 2006 15:04 MST")
+}
+
+// emailText renders the plain text part of an email
+func emailText(feed *gofeed.Feed, subject string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", subject)
+	if len(feed.Items) == 0 {
+		b.WriteString("\nNo activity.\n")
+	}
+	for _, item := range feed.Items {
+		fmt.Fprintf(&b, "\n%s\n", item.Title)
+		if published := emailItemTime(item); published != "" {
+			fmt.Fprintf(&b, "%s\n", published)
+		}
+		if item.Link != "" {
+			fmt.Fprintf(&b, "%s\n", item.Link)
+		}
+		if text := htmlPlainText(itemBody(item)); text != "" {
+			fmt.Fprintf(&b, "\n%s\n", text)
+		}
+	}
+	return b.String()
+}
+
+// htmlPlainText converts an HTML fragment to plain text, keeping a line for each block element
+func htmlPlainText(fragment string) string {
+	blockRegex := regexp.MustCompile(`(?i)<(?:br|/?(?:div|p|li|ul|ol|h[1-6]|blockquote|pre|tr))\b[^>]*>`)
+	tagRegex := regexp.MustCompile(`<[^>]*>`)
+	text := html.UnescapeString(tagRegex.ReplaceAllString(blockRegex.ReplaceAllString(fragment, "\n"), " "))
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// emailHTML renders the HTML part of an email, using the items' existing HTML bodies
+func emailHTML(feed *gofeed.Feed, subject string) string {
+	var htmlParts []string
+	htmlParts = append(htmlParts, fmt.Sprintf(
+		"<!DOCTYPE html><html><head><meta charset='utf-8'><title>%s</title></head>"+
+			"<body style='font-family: sans-serif; max-width: 720px;'>"+
+			"<h1 style='font-size: 20px;'>%s</h1>",
+		html.EscapeString(subject),
+		html.EscapeString(subject),
+	))
+	if len(feed.Items) == 0 {
+		htmlParts = append(htmlParts, "<p>No activity.</p>")
+	}
+
+	for _, item := range feed.Items {
+		title := html.EscapeString(item.Title)
+		if item.Link != "" {
+			title = fmt.Sprintf("<a href='%s'>%s</a>", html.EscapeString(item.Link), title)
+		}
+		htmlParts = append(htmlParts, fmt.Sprintf(
+			"<div style='margin-bottom: 24px;'>"+
+				"<h2 style='font-size: 16px; margin: 0 0 4px;'>%s</h2>"+
+				"<div style='color: #666; font-size: 12px; margin-bottom: 8px;'>%s</div>"+
+				"%s"+
+				"</div>",
+			title,
+			html.EscapeString(emailItemTime(item)),
+			itemBody(item),
+		))
+	}
+
+	htmlParts = append(htmlParts, "</body></html>")
+	return strings.Join(htmlParts, "")
+}
+
+// appendMbox appends a message to an mbox file, creating it if needed. Lines starting with "From " are
+// quoted as in mboxrd.
+func appendMbox(path, from string, now time.Time, message []byte) error {
+	fromLineRegex := regexp.MustCompile(`(?m)^(>*From )`)
+	body := strings.ReplaceAll(string(message), "\r\n", "\n")
+	body = fromLineRegex.ReplaceAllString(body, ">$1")
+	if !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "From %s %s\n%s\n", from, now.UTC().Format(time.ANSIC), body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// send delivers a message through the SMTP server
+func (m *Mailer) send(from string, to []string, message []byte) error {
+	host, port, err := net.SplitHostPort(m.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP address %s: %w", m.Addr, err)
+	}
+	timeout := m.Timeout
+	if timeout <= 0 {
+		timeout = defaultFetchTimeout
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	if port == "465" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", m.Addr, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", m.Addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connecting to SMTP server: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("connecting to SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("starting TLS: %w", err)
+		}
+	}
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, host)); err != nil {
+			return fmt.Errorf("authenticating to SMTP server: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("sending email to %s: %w", recipient, err)
+		}
+	}
+	data, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	if _, err := data.Write(message); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	if err := data.Close(); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+	return client.Quit()
+}
+
+// loadSMTPPassword reads the SMTP password from a file, falling back to the environment
+func loadSMTPPassword(passwordFile string) (string, error) {
+	if passwordFile == "" {
+		return os.Getenv(smtpPasswordEnvVar), nil
+	}
+	data, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return "", fmt.Errorf("reading SMTP password file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// emailTestFeed is a consolidated feed with a push and a pull request
+func emailTestFeed() *gofeed.Feed {
+	published := time.Date(2025, 9, 15, 3, 0, 0, 0, time.UTC)
+	return &gofeed.Feed{
+		Title: "cdzombak's GitHub activity",
+		Items: []*gofeed.Item{
+			{
+				GUID:            "consolidated-dotfiles-main-1757905200",
+				Title:           "cdzombak pushed 2 commits to dotfiles/main",
+				Link:            "https://github.com/cdzombak/dotfiles/compare/a0a0a0a0a0...8e9b024bed",
+				Content:         "<div><div><a href='https://github.com/cdzombak/dotfiles/commit/8e9b024'><code>8e9b024</code></a> Fix zsh prompt</div><div><a href='https://github.com/cdzombak/dotfiles/commit/b19a1b6'><code>b19a1b6</code></a> Use &lt;fzf&gt;</div></div>",
+				PublishedParsed: &published,
+			},
+			{
+				GUID:            "tag:github.com,2008:PullRequestEvent/1",
+				Title:           "cdzombak opened PR #264 in mmcdole/gofeed: Add JSON & YAML output",
+				Link:            "https://github.com/mmcdole/gofeed/pull/264",
+				PublishedParsed: &published,
+			},
+		},
+	}
+}
+
+func testEmail() *Email {
+	return &Email{
+		From: &mail.Address{Name: "ghfeed", Address: "ghfeed@example.com"},
+		To:   []*mail.Address{{Address: "team@example.com"}, {Name: "Zoë", Address: "zoe@example.com"}},
+	}
+}
+
+// readEmail parses a rendered email, returning its headers and its decoded parts by content type
+func readEmail(t *testing.T, data []byte) (mail.Header, map[string]string) {
+	t.Helper()
+	message, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("email isn't a valid message: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("email Content-Type = %q, want multipart/alternative", message.Header.Get("Content-Type"))
+	}
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading email part: %v", err)
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("reading email part: %v", err)
+		}
+		parts[part.Header.Get("Content-Type")] = string(content)
+	}
+	return message.Header, parts
+}
+
+func TestRenderEmail(t *testing.T) {
+	now := time.Date(2025, 9, 15, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	if err := renderEmail(&buf, emailTestFeed(), testEmail(), now); err != nil {
+		t.Fatalf("renderEmail() error = %v", err)
+	}
+	if strings.Contains(strings.ReplaceAll(buf.String(), "\r\n", ""), "\n") {
+		t.Errorf("renderEmail() has bare LF line endings")
+	}
+
+	header, parts := readEmail(t, buf.Bytes())
+	expectedHeaders := map[string]string{
+		"From":         `"ghfeed" <ghfeed@example.com>`,
+		"To":           `<team@example.com>, =?utf-8?q?Zo=C3=AB?= <zoe@example.com>`,
+		"Subject":      "cdzombak's GitHub activity",
+		"Date":         "Mon, 15 Sep 2025 12:00:00 +0000",
+		"MIME-Version": "1.0",
+	}
+	for name, want := range expectedHeaders {
+		if got := header.Get(name); got != want {
+			t.Errorf("renderEmail() %s = %q, want %q", name, got, want)
+		}
+	}
+	if id := header.Get("Message-ID"); !strings.HasPrefix(id, "<ghfeed.1757937600.") || !strings.HasSuffix(id, "@example.com>") {
+		t.Errorf("renderEmail() Message-ID = %q", id)
+	}
+
+	text := parts["text/plain; charset=utf-8"]
+	wantText := `cdzombak's GitHub activity
+
+cdzombak pushed 2 commits to dotfiles/main
+Mon Sep 15, 2025 03:00 UTC
+https://github.com/cdzombak/dotfiles/compare/a0a0a0a0a0...8e9b024bed
+
+8e9b024 Fix zsh prompt
+b19a1b6 Use <fzf>
+
+cdzombak opened PR #264 in mmcdole/gofeed: Add JSON & YAML output
+Mon Sep 15, 2025 03:00 UTC
+https://github.com/mmcdole/gofeed/pull/264
+`
+	if text != strings.ReplaceAll(wantText, "\n", "\r\n") {
+		t.Errorf("renderEmail() text part =\n%s\nwant\n%s", text, wantText)
+	}
+
+	htmlPart := parts["text/html; charset=utf-8"]
+	for _, want := range []string{
+		"<h1 style='font-size: 20px;'>cdzombak&#39;s GitHub activity</h1>",
+		"<a href='https://github.com/mmcdole/gofeed/pull/264'>cdzombak opened PR #264 in mmcdole/gofeed: Add JSON &amp; YAML output</a>",
+		emailTestFeed().Items[0].Content,
+	} {
+		if !strings.Contains(htmlPart, want) {
+			t.Errorf("renderEmail() HTML part doesn't contain %q:\n%s", want, htmlPart)
+		}
+	}
+}
+
+func TestRenderEmailSubject(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderEmail(&buf, &gofeed.Feed{Title: "Équipe activity"}, testEmail(), time.Now()); err != nil {
+		t.Fatalf("renderEmail() error = %v", err)
+	}
+	header, parts := readEmail(t, buf.Bytes())
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+	if err != nil || subject != "Équipe activity" {
+		t.Errorf("renderEmail() Subject = %q, decoded %q, want Équipe activity", header.Get("Subject"), subject)
+	}
+	if !strings.Contains(parts["text/plain; charset=utf-8"], "No activity.") {
+		t.Errorf("renderEmail() text part for an empty feed = %q, want No activity.", parts["text/plain; charset=utf-8"])
+	}
+}
+
+func TestHTMLPlainText(t *testing.T) {
+	tests := []struct {
+		fragment string
+		expected string
+	}{
+		{"<div>one</div><div>two <b>bold</b></div>", "one\ntwo bold"},
+		{"line<br>next<br/>last", "line\nnext\nlast"},
+		{"<ul><li>a</li><li>b &amp; c</li></ul>", "a\nb & c"},
+		{"<a href='x'><code>8e9b024</code></a>Fix", "8e9b024 Fix"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := htmlPlainText(tt.fragment); got != tt.expected {
+			t.Errorf("htmlPlainText(%q) = %q, want %q", tt.fragment, got, tt.expected)
+		}
+	}
+}
+
+func TestDeliverEmailMbox(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.mbox")
+	email := testEmail()
+	email.MboxPath = path
+
+	now := time.Date(2025, 9, 15, 12, 0, 0, 0, time.UTC)
+	for range 2 {
+		if err := email.deliver(io.Discard, emailTestFeed(), now); err != nil {
+			t.Fatalf("deliver() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mbox := string(data)
+	if n := strings.Count(mbox, "\nFrom ghfeed@example.com Mon Sep 15 12:00:00 2025\n"); !strings.HasPrefix(mbox, "From ghfeed@example.com Mon Sep 15 12:00:00 2025\n") || n != 1 {
+		t.Errorf("mbox doesn't start two messages with From lines:\n%s", mbox)
+	}
+	if strings.Contains(mbox, "\r\n") {
+		t.Errorf("mbox has CRLF line endings")
+	}
+}
+
+func TestAppendMbox(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.mbox")
+	message := "Subject: Test\r\n\r\nFrom now on\r\n>From the top\r\nFrom"
+	if err := appendMbox(path, "ghfeed@example.com", time.Date(2025, 9, 15, 12, 0, 0, 0, time.UTC), []byte(message)); err != nil {
+		t.Fatalf("appendMbox() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "From ghfeed@example.com Mon Sep 15 12:00:00 2025\nSubject: Test\n\n>From now on\n>>From the top\nFrom\n\n"
+	if string(data) != expected {
+		t.Errorf("appendMbox() wrote %q, want %q", data, expected)
+	}
+}
+
+func TestDeliverEmailStdout(t *testing.T) {
+	var stdout bytes.Buffer
+	if err := testEmail().deliver(&stdout, emailTestFeed(), time.Now()); err != nil {
+		t.Fatalf("deliver() error = %v", err)
+	}
+	if header, _ := readEmail(t, stdout.Bytes()); header.Get("Subject") != "cdzombak's GitHub activity" {
+		t.Errorf("deliver() wrote Subject %q to stdout", header.Get("Subject"))
+	}
+}
+
+// fakeSMTPServer accepts one connection and records the SMTP transaction
+type fakeSMTPServer struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	auth     string
+	from     string
+	to       []string
+	data     string
+	rejectTo string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTPServer{listener: listener}
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer s.wg.Done()
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch {
+		case command == "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case command == "AUTH":
+			s.auth = line
+			reply("235 Authenticated")
+		case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+			s.from = line[len("MAIL FROM:"):]
+			reply("250 OK")
+		case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+			to := line[len("RCPT TO:"):]
+			if to == s.rejectTo {
+				reply("550 No such user")
+				continue
+			}
+			s.to = append(s.to, to)
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestDeliverEmailSMTP(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	email := testEmail()
+	email.SMTP = &Mailer{Addr: server.listener.Addr().String(), Username: "ghfeed", Password: "hunter2", Timeout: 5 * time.Second}
+
+	if err := email.deliver(io.Discard, emailTestFeed(), time.Now()); err != nil {
+		t.Fatalf("deliver() error = %v", err)
+	}
+	server.wg.Wait()
+
+	if server.auth != "AUTH PLAIN AGdoZmVlZABodW50ZXIy" {
+		t.Errorf("SMTP auth = %q, want PLAIN for ghfeed", server.auth)
+	}
+	if server.from != "<ghfeed@example.com>" || strings.Join(server.to, ",") != "<team@example.com>,<zoe@example.com>" {
+		t.Errorf("SMTP envelope = %s to %v", server.from, server.to)
+	}
+	if header, _ := readEmail(t, []byte(server.data)); header.Get("Subject") != "cdzombak's GitHub activity" {
+		t.Errorf("SMTP message Subject = %q", header.Get("Subject"))
+	}
+}
+
+func TestDeliverEmailSMTPRejected(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	server.rejectTo = "<zoe@example.com>"
+	email := testEmail()
+	email.SMTP = &Mailer{Addr: server.listener.Addr().String(), Timeout: 5 * time.Second}
+
+	err := email.deliver(io.Discard, emailTestFeed(), time.Now())
+	if err == nil || !strings.Contains(err.Error(), "zoe@example.com") {
+		t.Errorf("deliver() error = %v, want a rejected recipient", err)
+	}
+}
+
+func TestLoadSMTPPassword(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte(" secret with spaces \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := loadSMTPPassword(path); err != nil || got != " secret with spaces " {
+		t.Errorf("loadSMTPPassword(file) = %q, %v", got, err)
+	}
+
+	t.Setenv(smtpPasswordEnvVar, "from-env")
+	if got, err := loadSMTPPassword(""); err != nil || got != "from-env" {
+		t.Errorf("loadSMTPPassword(\"\") = %q, %v, want from-env", got, err)
+	}
+}
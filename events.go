@@ -0,0 +1,410 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Event is a single entry from the GitHub REST Events API (e.g., /users/{user}/events)
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Actor     EventActor      `json:"actor"`
+	Repo      EventRepo       `json:"repo"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// EventActor is the user who performed an event
+type EventActor struct {
+	Login string `json:"login"`
+}
+
+// EventRepo is the repository an event happened in
+type EventRepo struct {
+	// Name is the repository's full name, e.g. "cdzombak/dotfiles"
+	Name string `json:"name"`
+}
+
+type pushEventPayload struct {
+	Ref     string `json:"ref"`
+	Head    string `json:"head"`
+	Before  string `json:"before"`
+	Size    int    `json:"size"`
+	Commits []struct {
+		SHA     string `json:"sha"`
+		Message string `json:"message"`
+	} `json:"commits"`
+}
+
+type pullRequestEventPayload struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title     string `json:"title"`
+		HTMLURL   string `json:"html_url"`
+		Additions int    `json:"additions"`
+		Deletions int    `json:"deletions"`
+		Merged    bool   `json:"merged"`
+	} `json:"pull_request"`
+}
+
+type refEventPayload struct {
+	Ref     string `json:"ref"`
+	RefType string `json:"ref_type"`
+}
+
+type forkEventPayload struct {
+	Forkee struct {
+		FullName string `json:"full_name"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"forkee"`
+}
+
+type issueEventPayload struct {
+	Action string `json:"action"`
+	Issue  struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+	} `json:"issue"`
+	Comment struct {
+		HTMLURL string `json:"html_url"`
+	} `json:"comment"`
+}
+
+type releaseEventPayload struct {
+	Action  string `json:"action"`
+	Release struct {
+		TagName string `json:"tag_name"`
+		Name    string `json:"name"`
+		HTMLURL string `json:"html_url"`
+	} `json:"release"`
+}
+
+// fetchEventsFeed fetches a user's events from the REST Events API and consolidates them into a feed.
+// target is either a username or a full Events API URL.
+func fetchEventsFeed(fetcher *Fetcher, target string, opts Options) (*gofeed.Feed, error) {
+	host := opts.Host
+	if host == "" {
+		host = eventsHost(target)
+	}
+
+	apiURL := eventsAPIURL(target, host)
+	events, err := fetcher.fetchEvents(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	username := eventsUsername(apiURL, events)
+	feed := &gofeed.Feed{
+		Title: fmt.Sprintf("%s's Activity", username),
+		Link:  fmt.Sprintf("%s/%s", hostURL(host), username),
+	}
+	if len(events) > 0 {
+		updated := events[0].CreatedAt
+		feed.Updated = updated.Format(time.RFC3339)
+		feed.UpdatedParsed = &updated
+	}
+
+	return consolidateActivities(feed, extractEventActivities(events, host), username, host, opts), nil
+}
+
+// fetchEvents downloads and decodes a page of events from the REST Events API
+func (f *Fetcher) fetchEvents(apiURL string) ([]Event, error) {
+	var events []Event
+	err := f.fetch(apiURL, "application/vnd.github+json", func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(&events)
+	})
+	return events, err
+}
+
+// eventsHost determines the GitHub hostname for an Events API target, defaulting to github.com
+func eventsHost(target string) string {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Host == "" || parsed.Host == "api.github.com" {
+		return defaultHost
+	}
+	return strings.ToLower(parsed.Host)
+}
+
+// eventsAPIURL returns the Events API URL for target, which may already be a URL or just a username
+func eventsAPIURL(target, host string) string {
+	if strings.HasPrefix(target, "https://") || strings.HasPrefix(target, "http://") {
+		return target
+	}
+
+	// github.com's API lives on its own host; GitHub Enterprise Server serves it under /api/v3
+	apiBase := hostURL(host) + "/api/v3"
+	if host == defaultHost {
+		apiBase = "https://api.github.com"
+	}
+	return fmt.Sprintf("%s/users/%s/events?per_page=100", apiBase, url.PathEscape(target))
+}
+
+// eventsUsername extracts the username or organization from an Events API URL, falling back to the first
+// event's actor
+func eventsUsername(apiURL string, events []Event) string {
+	userRegex := regexp.MustCompile(`/(?:users|orgs)/([^/]+)/events`)
+	matches := userRegex.FindStringSubmatch(apiURL)
+	if len(matches) > 1 {
+		return matches[1]
+	}
+
+	for _, event := range events {
+		if event.Actor.Login != "" {
+			return event.Actor.Login
+		}
+	}
+
+	return "user"
+}
+
+// extractEventActivities maps events onto the same push and simplified item model used for Atom feeds
+func extractEventActivities(events []Event, host string) []Activity {
+	activities := []Activity{}
+	for _, event := range events {
+		// The Atom feed's tag creations are pushes without commits, which are dropped; drop them here too
+		if isTagCreateEvent(event) {
+			logger.Debug("dropped tag creation", "repo", event.Repo.Name)
+			continue
+		}
+		activities = append(activities, extractEventActivity(event, host))
+	}
+	return activities
+}
+
+// isTagCreateEvent determines if an event is the creation of a tag
+func isTagCreateEvent(event Event) bool {
+	var payload refEventPayload
+	return event.Type == "CreateEvent" && json.Unmarshal(event.Payload, &payload) == nil && payload.RefType == "tag"
+}
+
+// extractEventActivity maps a single event onto a push or a simplified item
+func extractEventActivity(event Event, host string) Activity {
+	username := event.Actor.Login
+	repoLink := fmt.Sprintf("%s/%s", hostURL(host), event.Repo.Name)
+
+	switch event.Type {
+	case "PushEvent":
+		var payload pushEventPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			if push := extractPushEventActivity(event, payload, host); push != nil {
+				return Activity{Push: push}
+			}
+		}
+
+	case "PullRequestEvent":
+		var payload pullRequestEventPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			action := payload.Action
+			if action == "closed" && payload.PullRequest.Merged {
+				action = "merged"
+			}
+			link := payload.PullRequest.HTMLURL
+			if link == "" {
+				link = fmt.Sprintf("%s/pull/%d", repoLink, payload.Number)
+			}
+			diffStats := ""
+			if payload.PullRequest.Additions > 0 || payload.PullRequest.Deletions > 0 {
+				diffStats = fmt.Sprintf("+%s -%s", formatCount(payload.PullRequest.Additions), formatCount(payload.PullRequest.Deletions))
+			}
+			// The API's text is plain, where the Atom feed's is HTML
+			prTitle := html.EscapeString(payload.PullRequest.Title)
+			item := eventItem(event, host, "", link)
+			return Activity{Item: createPullRequestItem(item, username, action, strconv.Itoa(payload.Number), event.Repo.Name, prTitle, diffStats)}
+		}
+
+	case "CreateEvent":
+		var payload refEventPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			switch payload.RefType {
+			case "branch":
+				item := eventItem(event, host, "", fmt.Sprintf("%s/tree/%s", repoLink, refPath(payload.Ref)))
+				return Activity{Item: createBranchCreateItem(item, username, html.EscapeString(payload.Ref), event.Repo.Name)}
+			case "repository":
+				title := fmt.Sprintf("%s created repository %s", username, event.Repo.Name)
+				return Activity{Item: simplifyOtherActivity(eventItem(event, host, title, repoLink), username)}
+			}
+		}
+
+	case "DeleteEvent":
+		var payload refEventPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			item := eventItem(event, host, "", repoLink)
+			switch payload.RefType {
+			case "tag":
+				_, repoName := splitRepoName(event.Repo.Name)
+				return Activity{Item: createTagDeleteItem(item, username, html.EscapeString(payload.Ref), repoName, repoLink)}
+			case "branch":
+				return Activity{Item: simplifyBranchDelete(item, username)}
+			}
+		}
+
+	case "ForkEvent":
+		var payload forkEventPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			item := eventItem(event, host, "", payload.Forkee.HTMLURL)
+			return Activity{Item: createForkItem(item, username, event.Repo.Name, html.EscapeString(payload.Forkee.FullName))}
+		}
+
+	case "IssuesEvent", "IssueCommentEvent":
+		var payload issueEventPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			title := fmt.Sprintf("%s %s issue #%d in %s", username, payload.Action, payload.Issue.Number, event.Repo.Name)
+			link := payload.Issue.HTMLURL
+			if event.Type == "IssueCommentEvent" {
+				title = fmt.Sprintf("%s commented on issue #%d in %s", username, payload.Issue.Number, event.Repo.Name)
+				if payload.Comment.HTMLURL != "" {
+					link = payload.Comment.HTMLURL
+				}
+			}
+			if payload.Issue.Title != "" {
+				title += ": " + payload.Issue.Title
+			}
+			return Activity{Item: simplifyOtherActivity(eventItem(event, host, title, link), username)}
+		}
+
+	case "ReleaseEvent":
+		var payload releaseEventPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			name := payload.Release.Name
+			if name == "" {
+				name = payload.Release.TagName
+			}
+			title := fmt.Sprintf("%s %s release %s in %s", username, payload.Action, name, event.Repo.Name)
+			return Activity{Item: simplifyOtherActivity(eventItem(event, host, title, payload.Release.HTMLURL), username)}
+		}
+
+	case "WatchEvent":
+		title := fmt.Sprintf("%s starred %s", username, event.Repo.Name)
+		return Activity{Item: simplifyOtherActivity(eventItem(event, host, title, repoLink), username)}
+
+	case "PublicEvent":
+		title := fmt.Sprintf("%s made %s public", username, event.Repo.Name)
+		return Activity{Item: simplifyOtherActivity(eventItem(event, host, title, repoLink), username)}
+	}
+
+	// Anything unrecognized or malformed gets a generic entry
+	title := fmt.Sprintf("%s: %s in %s", username, strings.TrimSuffix(event.Type, "Event"), event.Repo.Name)
+	return Activity{Item: simplifyOtherActivity(eventItem(event, host, title, repoLink), username)}
+}
+
+// extractPushEventActivity converts a PushEvent into a BranchActivity, returning nil if it has no commits
+func extractPushEventActivity(event Event, payload pushEventPayload, host string) *BranchActivity {
+	owner, repoName := splitRepoName(event.Repo.Name)
+	repoLink := fmt.Sprintf("%s/%s", hostURL(host), event.Repo.Name)
+
+	var commits []Commit
+	for _, c := range payload.Commits {
+		commits = append(commits, Commit{
+			Hash:    shortHash(c.SHA, 7),
+			Message: html.EscapeString(strings.TrimSpace(strings.SplitN(c.Message, "\n", 2)[0])),
+			Link:    fmt.Sprintf("%s/commit/%s", repoLink, c.SHA),
+		})
+	}
+
+	// The API may omit commit details; fall back to the pushed head, like the Atom link-only fallback
+	if len(commits) == 0 && payload.Head != "" {
+		commits = append(commits, Commit{
+			Hash:    shortHash(payload.Head, 7),
+			Message: "Commit " + shortHash(payload.Head, 7),
+			Link:    fmt.Sprintf("%s/commit/%s", repoLink, payload.Head),
+		})
+	}
+
+	if len(commits) == 0 {
+		return nil
+	}
+
+	// The API lists commits oldest-to-newest; match extractCommitsFromContent's newest-first order
+	slices.Reverse(commits)
+
+	// Same compare link GitHub uses for the push in its Atom feed
+	compareLink := commits[0].Link
+	if payload.Before != "" && strings.Trim(payload.Before, "0") != "" && payload.Head != "" {
+		compareLink = fmt.Sprintf("%s/compare/%s...%s", repoLink, shortHash(payload.Before, 10), shortHash(payload.Head, 10))
+	}
+
+	total := len(commits)
+	moreLink := ""
+	if payload.Size > total {
+		total = payload.Size
+		moreLink = compareLink
+	}
+
+	createdAt := event.CreatedAt
+	return &BranchActivity{
+		Actor:           event.Actor.Login,
+		Owner:           owner,
+		Repo:            repoName,
+		Branch:          strings.TrimPrefix(payload.Ref, "refs/heads/"),
+		Commits:         commits,
+		LatestTime:      &createdAt,
+		CompareLink:     compareLink,
+		TotalCommits:    total,
+		MoreCommitsLink: moreLink,
+	}
+}
+
+// eventItem creates the base item for an event, with the same GUID format GitHub uses in its Atom feeds
+func eventItem(event Event, host, title, link string) *gofeed.Item {
+	createdAt := event.CreatedAt
+	return &gofeed.Item{
+		Title:           title,
+		Link:            link,
+		Published:       createdAt.Format(time.RFC3339),
+		PublishedParsed: &createdAt,
+		Updated:         createdAt.Format(time.RFC3339),
+		UpdatedParsed:   &createdAt,
+		GUID:            fmt.Sprintf("tag:%s,2008:%s/%s", host, event.Type, event.ID),
+	}
+}
+
+// refPath escapes a branch or tag name for use in a URL path, keeping its slashes
+func refPath(ref string) string {
+	return (&url.URL{Path: ref}).EscapedPath()
+}
+
+// splitRepoName splits a full repository name like "cdzombak/dotfiles" into owner and name
+func splitRepoName(fullName string) (string, string) {
+	owner, name, found := strings.Cut(fullName, "/")
+	if !found {
+		return "", fullName
+	}
+	return owner, name
+}
+
+// shortHash abbreviates a commit SHA to n characters
+func shortHash(sha string, n int) string {
+	if len(sha) > n {
+		return sha[:n]
+	}
+	return sha
+}
+
+// formatCount formats n with thousands separators, as GitHub does for diff stats (e.g., 3,415)
+func formatCount(n int) string {
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	digits := strconv.Itoa(n)
+
+	var parts []string
+	for len(digits) > 3 {
+		parts = append([]string{digits[len(digits)-3:]}, parts...)
+		digits = digits[:len(digits)-3]
+	}
+	parts = append([]string{digits}, parts...)
+	return strings.Join(parts, ",")
+}
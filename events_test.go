@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// newEventsStub serves the recorded Events API fixture at /users/cdzombak/events
+func newEventsStub(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	fixture, err := os.ReadFile("testdata/events.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/cdzombak/events" {
+			http.NotFound(w, r)
+			return
+		}
+		if accept := r.Header.Get("Accept"); accept != "application/vnd.github+json" {
+			t.Errorf("Accept header = %q, want application/vnd.github+json", accept)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture)
+	}))
+}
+
+func TestFetchEventsFeed(t *testing.T) {
+	server := newEventsStub(t)
+	defer server.Close()
+
+	result, err := fetchEventsFeed(newTestFetcher(), server.URL+"/users/cdzombak/events", Options{ConsolidatePushes: true, Host: "github.com"})
+	if err != nil {
+		t.Fatalf("fetchEventsFeed() error = %v", err)
+	}
+
+	if result.Title != "cdzombak's Activity" {
+		t.Errorf("fetchEventsFeed().Title = %v", result.Title)
+	}
+	if result.Link != "https://github.com/cdzombak" {
+		t.Errorf("fetchEventsFeed().Link = %v", result.Link)
+	}
+
+	expectedTitles := []string{
+		"cdzombak pushed 2 commits to dotfiles/master",
+		"cdzombak pushed 40 commits to ghfeed/main",
+		"cdzombak opened PR #264 in mmcdole/gofeed: Allow outputting RSS, Atom, and JSON feeds",
+		"cdzombak created branch cdz/feed-creation in cdzombak/gofeed",
+		"cdzombak forked mmcdole/gofeed",
+		"cdzombak deleted tag v0.0.6 in homebrew-gomod",
+		"cdzombak opened issue #12 in cdzombak/ghfeed: Support the Events API",
+		"cdzombak starred golang/go",
+		"cdzombak: Sponsorship in golang/go",
+	}
+	if len(result.Items) != len(expectedTitles) {
+		t.Fatalf("fetchEventsFeed() items count = %d, want %d", len(result.Items), len(expectedTitles))
+	}
+	for i, expected := range expectedTitles {
+		if result.Items[i].Title != expected {
+			t.Errorf("fetchEventsFeed().Items[%d].Title = %v, want %v", i, result.Items[i].Title, expected)
+		}
+	}
+
+	// The truncated push should link to GitHub's compare view for the whole push
+	truncated := result.Items[1]
+	expectedLink := "https://github.com/cdzombak/ghfeed/compare/c0ffee0000...c0ffee0000"
+	if truncated.Link != expectedLink {
+		t.Errorf("truncated push link = %v, want %v", truncated.Link, expectedLink)
+	}
+	if !strings.Contains(truncated.Content, "38 more commits") {
+		t.Errorf("truncated push content missing more commits note, got %v", truncated.Content)
+	}
+
+	// GUIDs for simplified items match the ones GitHub uses in its Atom feed
+	if result.Items[2].GUID != "tag:github.com,2008:PullRequestEvent/52341232000" {
+		t.Errorf("PR item GUID = %v", result.Items[2].GUID)
+	}
+}
+
+// TestEventsMatchAtomOutput checks that the same push and pull request produce identical items from either source
+func TestEventsMatchAtomOutput(t *testing.T) {
+	pushTime, _ := time.Parse(time.RFC3339, "2025-09-15T01:28:02Z")
+	prTime, _ := time.Parse(time.RFC3339, "2025-09-14T22:58:34Z")
+
+	atomFeed := &gofeed.Feed{
+		Title: "cdzombak's Activity",
+		Link:  "https://github.com/cdzombak",
+		Items: []*gofeed.Item{
+			{
+				Title:           "cdzombak pushed dotfiles",
+				Content:         pushHTML,
+				Link:            "https://github.com/cdzombak/dotfiles/compare/3f1e2d4c5b...b19a1b604e",
+				PublishedParsed: &pushTime,
+				GUID:            "tag:github.com,2008:PushEvent/52341234567",
+			},
+			{
+				Title:           "cdzombak opened a pull request in mmcdole/gofeed",
+				Content:         pullRequestHTML,
+				Link:            "https://github.com/mmcdole/gofeed/pull/264",
+				PublishedParsed: &prTime,
+				UpdatedParsed:   &prTime,
+				GUID:            "tag:github.com,2008:PullRequestEvent/52341232000",
+			},
+		},
+	}
+
+	server := newEventsStub(t)
+	defer server.Close()
+
+	for _, consolidate := range []bool{true, false} {
+		opts := Options{ConsolidatePushes: consolidate, Host: "github.com"}
+		atomResult := consolidateCommits(atomFeed, opts)
+		eventsResult, err := fetchEventsFeed(newTestFetcher(), server.URL+"/users/cdzombak/events", opts)
+		if err != nil {
+			t.Fatalf("fetchEventsFeed() error = %v", err)
+		}
+
+		eventsByTitle := make(map[string]*gofeed.Item)
+		for _, item := range eventsResult.Items {
+			eventsByTitle[item.Title] = item
+		}
+
+		for _, atomItem := range atomResult.Items {
+			eventItem, ok := eventsByTitle[atomItem.Title]
+			if !ok {
+				t.Errorf("consolidate=%v: events source missing item %q", consolidate, atomItem.Title)
+				continue
+			}
+			if eventItem.Link != atomItem.Link {
+				t.Errorf("consolidate=%v: %q link = %v, Atom source gave %v", consolidate, atomItem.Title, eventItem.Link, atomItem.Link)
+			}
+			if eventItem.Content != atomItem.Content {
+				t.Errorf("consolidate=%v: %q content = %v, Atom source gave %v", consolidate, atomItem.Title, eventItem.Content, atomItem.Content)
+			}
+			if eventItem.GUID != atomItem.GUID {
+				t.Errorf("consolidate=%v: %q GUID = %v, Atom source gave %v", consolidate, atomItem.Title, eventItem.GUID, atomItem.GUID)
+			}
+		}
+	}
+}
+
+func TestEventsAPIURL(t *testing.T) {
+	tests := []struct {
+		target   string
+		host     string
+		expected string
+	}{
+		{"cdzombak", "github.com", "https://api.github.com/users/cdzombak/events?per_page=100"},
+		{"cdzombak", "git.corp.example.com", "https://git.corp.example.com/api/v3/users/cdzombak/events?per_page=100"},
+		{"https://api.github.com/users/cdzombak/events", "github.com", "https://api.github.com/users/cdzombak/events"},
+	}
+
+	for _, tt := range tests {
+		if result := eventsAPIURL(tt.target, tt.host); result != tt.expected {
+			t.Errorf("eventsAPIURL(%q, %q) = %v, want %v", tt.target, tt.host, result, tt.expected)
+		}
+	}
+
+	if host := eventsHost("https://api.github.com/users/cdzombak/events"); host != "github.com" {
+		t.Errorf("eventsHost(api.github.com) = %v, want github.com", host)
+	}
+	if host := eventsHost("https://git.corp.example.com/api/v3/users/cdzombak/events"); host != "git.corp.example.com" {
+		t.Errorf("eventsHost(GHES) = %v, want git.corp.example.com", host)
+	}
+	if host := eventsHost("cdzombak"); host != "github.com" {
+		t.Errorf("eventsHost(username) = %v, want github.com", host)
+	}
+
+	if username := eventsUsername("https://api.github.com/orgs/ourorg/events", nil); username != "ourorg" {
+		t.Errorf("eventsUsername(org) = %v, want ourorg", username)
+	}
+}
+
+// TestEventsEscapeText checks that the API's plain text is escaped before it reaches item HTML, which the
+// Atom feed's text already is, and that tag creations are dropped as they are from Atom feeds
+func TestEventsEscapeText(t *testing.T) {
+	events := []Event{
+		{
+			ID:        "1",
+			CreatedAt: time.Date(2025, 9, 15, 3, 0, 0, 0, time.UTC),
+			Type:      "PushEvent",
+			Actor:     EventActor{Login: "cdzombak"},
+			Repo:      EventRepo{Name: "cdzombak/dotfiles"},
+			Payload:   json.RawMessage(`{"ref": "refs/heads/main", "commits": [{"sha": "8e9b024bedc0ffee", "message": "<script>alert(1)</script> & more"}]}`),
+		},
+		{
+			ID:        "2",
+			CreatedAt: time.Date(2025, 9, 15, 2, 0, 0, 0, time.UTC),
+			Type:      "PullRequestEvent",
+			Actor:     EventActor{Login: "cdzombak"},
+			Repo:      EventRepo{Name: "mmcdole/gofeed"},
+			Payload:   json.RawMessage(`{"action": "opened", "number": 7, "pull_request": {"title": "Use <fzf> & fd"}}`),
+		},
+		{
+			ID:        "3",
+			CreatedAt: time.Date(2025, 9, 15, 1, 0, 0, 0, time.UTC),
+			Type:      "CreateEvent",
+			Actor:     EventActor{Login: "cdzombak"},
+			Repo:      EventRepo{Name: "cdzombak/dotfiles"},
+			Payload:   json.RawMessage(`{"ref": "cdz/<b>", "ref_type": "branch"}`),
+		},
+		{
+			ID:        "4",
+			CreatedAt: time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC),
+			Type:      "CreateEvent",
+			Actor:     EventActor{Login: "cdzombak"},
+			Repo:      EventRepo{Name: "cdzombak/dotfiles"},
+			Payload:   json.RawMessage(`{"ref": "v1.0.0", "ref_type": "tag"}`),
+		},
+	}
+
+	feed := consolidateActivities(&gofeed.Feed{}, extractEventActivities(events, "github.com"), "cdzombak", "github.com", Options{ConsolidatePushes: true})
+	expected := []string{
+		"&lt;script&gt;alert(1)&lt;/script&gt; &amp; more",
+		"Use &lt;fzf&gt; &amp; fd",
+		"cdz/&lt;b&gt;",
+	}
+	if len(feed.Items) != len(expected) {
+		t.Fatalf("consolidateActivities() items count = %d, want %d", len(feed.Items), len(expected))
+	}
+	for i, want := range expected {
+		if !strings.Contains(feed.Items[i].Content, want) {
+			t.Errorf("event %d content = %v, want it to contain %v", i, feed.Items[i].Content, want)
+		}
+		if strings.Contains(feed.Items[i].Content, "<script>") || strings.Contains(feed.Items[i].Content, "<fzf>") || strings.Contains(feed.Items[i].Content, "<b>") {
+			t.Errorf("event %d content contains unescaped text: %v", i, feed.Items[i].Content)
+		}
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := map[int]string{
+		0:       "0",
+		146:     "146",
+		3415:    "3,415",
+		1234567: "1,234,567",
+		-1000:   "-1,000",
+	}
+
+	for n, expected := range tests {
+		if result := formatCount(n); result != expected {
+			t.Errorf("formatCount(%d) = %v, want %v", n, result, expected)
+		}
+	}
+}
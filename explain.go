@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Explanation reports how each input item was transformed into the output feed, for -explain
+type Explanation struct {
+	Items []*ItemExplanation `json:"items"`
+
+	bySource map[*gofeed.Item]*ItemExplanation
+}
+
+// ItemExplanation describes what became of one input item
+type ItemExplanation struct {
+	// Index is the item's 1-based position in the input feed
+	Index int    `json:"index"`
+	GUID  string `json:"guid"`
+	Title string `json:"title"`
+	// Type is "push" for pushes; for other items in GitHub user feeds, what detectActivityType classified
+	// the item as; and "item" for other items in other feeds
+	Type        string   `json:"type"`
+	Repo        string   `json:"repo,omitempty"`
+	Branch      string   `json:"branch,omitempty"`
+	Commits     []string `json:"commits,omitempty"`
+	PullRequest string   `json:"pull_request,omitempty"`
+	// ConsolidationKey is the actor@owner/repo/branch group the push joined, when pushes are consolidated
+	ConsolidationKey string `json:"consolidation_key,omitempty"`
+	// OutputGUID is the GUID of the output item the input item ended up in
+	OutputGUID string `json:"output_guid,omitempty"`
+	// Dropped is why the item isn't in the output feed
+	Dropped string `json:"dropped,omitempty"`
+}
+
+// activitySources returns the input items an activity was made from
+func activitySources(activity Activity) []*gofeed.Item {
+	if activity.Push != nil {
+		return activity.Push.Sources
+	}
+	return activity.Sources
+}
+
+// describe starts the explanation with an entry for each of the feed's items, describing the activity
+// extracted from it. Like Explanation's other methods, it does nothing on a nil Explanation.
+func (e *Explanation) describe(feed *gofeed.Feed, activities []Activity, username, host, provider string) {
+	if e == nil {
+		return
+	}
+
+	e.Items = []*ItemExplanation{}
+	e.bySource = make(map[*gofeed.Item]*ItemExplanation)
+	for i, item := range feed.Items {
+		entry := &ItemExplanation{Index: i + 1, GUID: item.GUID, Title: item.Title}
+		e.Items = append(e.Items, entry)
+		e.bySource[item] = entry
+	}
+
+	// Only GitHub user feeds' items are classified by detectActivityType
+	detected := (provider == "" || provider == providerGitHub) && detectRepoFeed(feed, host) == nil
+	prRegex := regexp.MustCompile(`/(?:pull|pulls|merge_requests)/(\d+)`)
+
+	for _, activity := range activities {
+		for _, source := range activitySources(activity) {
+			entry := e.bySource[source]
+			if entry == nil {
+				continue
+			}
+			entry.Repo = activityRepo(activity, username)
+
+			if push := activity.Push; push != nil {
+				entry.Type = "push"
+				entry.Branch = push.Branch
+				for _, commit := range push.Commits {
+					entry.Commits = append(entry.Commits, commit.Hash)
+				}
+				continue
+			}
+
+			entry.Type = "item"
+			if detected {
+				entry.Type = detectActivityType(source).String()
+			}
+			if matches := prRegex.FindStringSubmatch(source.Link); matches != nil {
+				entry.PullRequest = matches[1]
+			}
+		}
+	}
+}
+
+// filtered records the input items of activities in before that a filter left out of after
+func (e *Explanation) filtered(before, after []Activity, reason string) []Activity {
+	if e == nil {
+		return after
+	}
+
+	kept := make(map[*gofeed.Item]bool)
+	for _, activity := range after {
+		for _, source := range activitySources(activity) {
+			kept[source] = true
+		}
+	}
+	for _, activity := range before {
+		for _, source := range activitySources(activity) {
+			if !kept[source] {
+				e.drop(source, reason)
+			}
+		}
+	}
+	return after
+}
+
+// drop records that an input item was left out of the output
+func (e *Explanation) drop(source *gofeed.Item, reason string) {
+	if e == nil {
+		return
+	}
+	if entry := e.bySource[source]; entry != nil && entry.Dropped == "" {
+		entry.Dropped = reason
+	}
+}
+
+// output records the output item that input items ended up in, and the consolidation key they joined
+func (e *Explanation) output(sources []*gofeed.Item, guid, key string) {
+	if e == nil {
+		return
+	}
+	for _, source := range sources {
+		if entry := e.bySource[source]; entry != nil {
+			entry.OutputGUID = guid
+			entry.ConsolidationKey = key
+		}
+	}
+}
+
+// digested records that output items were rolled up into digest items, given the digest GUID for each
+// output GUID
+func (e *Explanation) digested(guids map[string]string) {
+	if e == nil {
+		return
+	}
+	for _, entry := range e.Items {
+		if digest, ok := guids[entry.OutputGUID]; ok {
+			entry.OutputGUID = digest
+		}
+	}
+}
+
+// truncated records that output items were cut by -max-items
+func (e *Explanation) truncated(items []*gofeed.Item) {
+	if e == nil {
+		return
+	}
+	cut := make(map[string]bool)
+	for _, item := range items {
+		cut[item.GUID] = true
+	}
+	for _, entry := range e.Items {
+		if entry.OutputGUID != "" && cut[entry.OutputGUID] {
+			entry.OutputGUID = ""
+			entry.Dropped = "beyond -max-items"
+		}
+	}
+}
+
+// renderExplanation writes the explanation to w as "text" or "json"
+func renderExplanation(w io.Writer, e *Explanation, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(e)
+	}
+
+	var b strings.Builder
+	for _, entry := range e.Items {
+		fmt.Fprintf(&b, "%d. %s\n", entry.Index, entry.Title)
+		fields := [][2]string{
+			{"guid", entry.GUID},
+			{"type", entry.Type},
+			{"repo", entry.Repo},
+			{"branch", entry.Branch},
+			{"commits", strings.Join(entry.Commits, ", ")},
+			{"pull request", entry.PullRequest},
+			{"consolidation key", entry.ConsolidationKey},
+			{"output", entry.OutputGUID},
+			{"dropped", entry.Dropped},
+		}
+		for _, field := range fields {
+			if field[1] != "" {
+				fmt.Fprintf(&b, "   %s: %s\n", field[0], field[1])
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
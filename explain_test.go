@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// explainTestFeed is a GitHub user feed with two pushes to one branch, a pull request, and an
+// unrecognized activity
+func explainTestFeed() *gofeed.Feed {
+	at := func(hour int) *time.Time {
+		t := time.Date(2025, 9, 15, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	pushContent := func(hash, message string) string {
+		return `<a class="branch-name" href="/cdzombak/dotfiles/tree/main">main</a>` +
+			`<code><a href="/cdzombak/dotfiles/commit/` + hash + `">` + hash[:7] + `</a></code>` +
+			`<div><blockquote>` + message + `</blockquote></div>`
+	}
+
+	return &gofeed.Feed{
+		Link: "https://github.com/cdzombak",
+		Items: []*gofeed.Item{
+			{
+				GUID:            "tag:github.com,2008:PushEvent/2",
+				Title:           "cdzombak pushed dotfiles",
+				Link:            "https://github.com/cdzombak/dotfiles/compare/b19a1b604e...8e9b024bed",
+				Content:         pushContent("8e9b024bed", "Fix zsh prompt"),
+				PublishedParsed: at(3),
+			},
+			{
+				GUID:            "tag:github.com,2008:PullRequestEvent/1",
+				Title:           "cdzombak opened a pull request in mmcdole/gofeed",
+				Link:            "https://github.com/mmcdole/gofeed/pull/264",
+				PublishedParsed: at(2),
+			},
+			{
+				GUID:            "tag:github.com,2008:PushEvent/1",
+				Title:           "cdzombak pushed dotfiles",
+				Link:            "https://github.com/cdzombak/dotfiles/compare/a0a0a0a0a0...b19a1b604e",
+				Content:         pushContent("b19a1b604e", "WIP"),
+				PublishedParsed: at(1),
+			},
+			{
+				GUID:            "tag:github.com,2008:PublicEvent/1",
+				Title:           "cdzombak made cdzombak/ghfeed public",
+				Link:            "https://github.com/cdzombak/ghfeed",
+				PublishedParsed: at(0),
+			},
+		},
+	}
+}
+
+func TestExplain(t *testing.T) {
+	explanation := &Explanation{}
+	consolidateCommits(explainTestFeed(), Options{ConsolidatePushes: true, Explain: explanation})
+
+	pushGUID := "consolidated-dotfiles-main-" + "1757905200"
+	expected := []*ItemExplanation{
+		{Index: 1, GUID: "tag:github.com,2008:PushEvent/2", Title: "cdzombak pushed dotfiles", Type: "push", Repo: "cdzombak/dotfiles", Branch: "main", Commits: []string{"8e9b024"}, ConsolidationKey: "@/dotfiles/main", OutputGUID: pushGUID},
+		{Index: 2, GUID: "tag:github.com,2008:PullRequestEvent/1", Title: "cdzombak opened a pull request in mmcdole/gofeed", Type: "pull_request", Repo: "mmcdole/gofeed", PullRequest: "264", OutputGUID: "tag:github.com,2008:PullRequestEvent/1"},
+		{Index: 3, GUID: "tag:github.com,2008:PushEvent/1", Title: "cdzombak pushed dotfiles", Type: "push", Repo: "cdzombak/dotfiles", Branch: "main", Commits: []string{"b19a1b6"}, ConsolidationKey: "@/dotfiles/main", OutputGUID: pushGUID},
+		{Index: 4, GUID: "tag:github.com,2008:PublicEvent/1", Title: "cdzombak made cdzombak/ghfeed public", Type: "other", Repo: "cdzombak/ghfeed", OutputGUID: "tag:github.com,2008:PublicEvent/1"},
+	}
+
+	if len(explanation.Items) != len(expected) {
+		t.Fatalf("explanation has %d items, want %d", len(explanation.Items), len(expected))
+	}
+	for i, want := range expected {
+		if got := explanation.Items[i]; !reflect.DeepEqual(got, want) {
+			t.Errorf("explanation item %d = %+v, want %+v", i+1, got, want)
+		}
+	}
+}
+
+func TestExplainDropped(t *testing.T) {
+	explanation := &Explanation{}
+	consolidateCommits(explainTestFeed(), Options{
+		ConsolidatePushes: false,
+		ExcludeMatch:      regexp.MustCompile(`^WIP`),
+		MaxItems:          2,
+		Explain:           explanation,
+	})
+
+	expected := []struct {
+		output  string
+		dropped string
+	}{
+		{"individual-dotfiles-main-1757905200", ""},
+		{"tag:github.com,2008:PullRequestEvent/1", ""},
+		{"", "no commits matching -match/-exclude-match"},
+		{"", "beyond -max-items"},
+	}
+	for i, want := range expected {
+		got := explanation.Items[i]
+		if got.OutputGUID != want.output || got.Dropped != want.dropped || got.ConsolidationKey != "" {
+			t.Errorf("explanation item %d output = %q, dropped = %q, key = %q; want %q, %q, no key", i+1, got.OutputGUID, got.Dropped, got.ConsolidationKey, want.output, want.dropped)
+		}
+	}
+}
+
+func TestExplainRepoFeed(t *testing.T) {
+	explanation := &Explanation{}
+	consolidateCommits(parseTestFeed(t, "commits.atom"), Options{ConsolidatePushes: true, Explain: explanation})
+
+	for _, entry := range explanation.Items {
+		// Commits feed entries aren't classified by detectActivityType
+		if entry.Type != "push" || entry.Repo != "cdzombak/ghfeed" || entry.Branch != "main" || entry.OutputGUID == "" {
+			t.Errorf("explanation item %d = %+v, want a push to cdzombak/ghfeed main with output", entry.Index, entry)
+		}
+		if !strings.HasSuffix(entry.ConsolidationKey, "@cdzombak/ghfeed/main") {
+			t.Errorf("explanation item %d consolidation key = %q, want actor@cdzombak/ghfeed/main", entry.Index, entry.ConsolidationKey)
+		}
+	}
+}
+
+func TestRenderExplanation(t *testing.T) {
+	explanation := &Explanation{}
+	consolidateCommits(explainTestFeed(), Options{ConsolidatePushes: true, MaxItems: 2, Explain: explanation})
+
+	var text bytes.Buffer
+	if err := renderExplanation(&text, explanation, "text"); err != nil {
+		t.Fatalf("renderExplanation() error = %v", err)
+	}
+	wantText := `1. cdzombak pushed dotfiles
+   guid: tag:github.com,2008:PushEvent/2
+   type: push
+   repo: cdzombak/dotfiles
+   branch: main
+   commits: 8e9b024
+   consolidation key: @/dotfiles/main
+   output: consolidated-dotfiles-main-1757905200
+2. cdzombak opened a pull request in mmcdole/gofeed
+   guid: tag:github.com,2008:PullRequestEvent/1
+   type: pull_request
+   repo: mmcdole/gofeed
+   pull request: 264
+   output: tag:github.com,2008:PullRequestEvent/1
+`
+	if !strings.HasPrefix(text.String(), wantText) {
+		t.Errorf("renderExplanation(text) =\n%s\nwant prefix\n%s", text.String(), wantText)
+	}
+	if !strings.Contains(text.String(), "4. cdzombak made cdzombak/ghfeed public\n   guid: tag:github.com,2008:PublicEvent/1\n   type: other\n   repo: cdzombak/ghfeed\n   dropped: beyond -max-items\n") {
+		t.Errorf("renderExplanation(text) doesn't report the dropped item:\n%s", text.String())
+	}
+
+	var jsonOutput bytes.Buffer
+	if err := renderExplanation(&jsonOutput, explanation, "json"); err != nil {
+		t.Fatalf("renderExplanation() error = %v", err)
+	}
+	var decoded Explanation
+	if err := json.Unmarshal(jsonOutput.Bytes(), &decoded); err != nil {
+		t.Fatalf("renderExplanation(json) isn't JSON: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Items, explanation.Items) {
+		t.Errorf("renderExplanation(json) round-trips to %+v, want %+v", decoded.Items, explanation.Items)
+	}
+}
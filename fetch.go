@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// tokenEnvVar is the environment variable an access token is read from
+const tokenEnvVar = "GHFEED_TOKEN"
+
+// redactedToken replaces the access token wherever it would otherwise be displayed
+const redactedToken = "REDACTED"
+
+// Default fetch settings, overridable with -timeout and -retries
+const (
+	defaultFetchTimeout = 30 * time.Second
+	defaultFetchRetries = 3
+	defaultRetryBackoff = time.Second
+)
+
+// maxRetryBackoff caps retry delays, both doubling ones and servers' Retry-After, so retries without a timeout
+// don't wait for ages
+const maxRetryBackoff = 5 * time.Minute
+
+// Fetcher retrieves and parses upstream feeds, authenticating requests when a token is configured
+type Fetcher struct {
+	Client    *http.Client
+	Token     string
+	UserAgent string
+	// Timeout bounds the whole fetch, including retries; zero means no limit
+	Timeout time.Duration
+	// MaxRetries is how many times a failed request is retried after the first attempt
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry; it doubles for each subsequent retry, up to maxRetryBackoff
+	RetryBackoff time.Duration
+}
+
+// newFetcher creates a Fetcher with default settings that authenticates with the given token (which may be empty)
+func newFetcher(token string) *Fetcher {
+	return &Fetcher{
+		Client:       &http.Client{},
+		Token:        token,
+		UserAgent:    "ghfeed/" + version,
+		Timeout:      defaultFetchTimeout,
+		MaxRetries:   defaultFetchRetries,
+		RetryBackoff: defaultRetryBackoff,
+	}
+}
+
+// loadToken reads the access token from tokenFile if given, otherwise from the GHFEED_TOKEN environment variable
+func loadToken(tokenFile string) (string, error) {
+	if tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("reading token file: %w", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", tokenFile)
+		}
+		return token, nil
+	}
+
+	return strings.TrimSpace(os.Getenv(tokenEnvVar)), nil
+}
+
+// fetchFeed downloads and parses the feed at feedURL, retrying transient failures with exponential backoff.
+// Errors and the returned feed never contain the token.
+func (f *Fetcher) fetchFeed(feedURL string) (*gofeed.Feed, error) {
+	var feed *gofeed.Feed
+	err := f.fetch(feedURL, "", func(body io.Reader) error {
+		var err error
+		feed, err = gofeed.NewParser().Parse(body)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// GitHub's private feeds embed the token in their self links
+	token := f.redactionToken(feedURL)
+	feed.Link = redactURL(feed.Link, token)
+	feed.FeedLink = redactURL(feed.FeedLink, token)
+	for i := range feed.Links {
+		feed.Links[i] = redactURL(feed.Links[i], token)
+	}
+
+	return feed, nil
+}
+
+// fetch downloads rawURL and hands the response body to parse, retrying transient failures with
+// exponential backoff. accept sets the request's Accept header when non-empty. Errors never contain the token.
+func (f *Fetcher) fetch(rawURL, accept string, parse func(io.Reader) error) error {
+	token := f.redactionToken(rawURL)
+
+	ctx := context.Background()
+	if f.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.Timeout)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		retryDelay, err := f.fetchOnce(ctx, rawURL, accept, token, parse)
+		if err == nil {
+			return nil
+		}
+		if retryDelay < 0 || attempt >= f.MaxRetries {
+			return err
+		}
+
+		if retryDelay == 0 {
+			retryDelay = f.retryBackoff(attempt)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(retryDelay).After(deadline) {
+			// Waiting would run past the timeout; report the failure we have rather than a timeout
+			return err
+		}
+		logger.Debug("retrying fetch", "url", redactURL(rawURL, token), "attempt", attempt+1, "delay", retryDelay, "error", err)
+
+		timer := time.NewTimer(retryDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryBackoff returns the delay before retrying after the given failed attempt: RetryBackoff, doubled for each
+// earlier retry, up to maxRetryBackoff
+func (f *Fetcher) retryBackoff(attempt int) time.Duration {
+	delay := f.RetryBackoff
+	for i := 0; i < attempt && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
+
+// fetchOnce makes a single attempt to fetch and parse rawURL. On failure it also returns the delay
+// to wait before retrying: zero to use the default backoff, or negative if the failure isn't retryable.
+func (f *Fetcher) fetchOnce(ctx context.Context, rawURL, accept, token string, parse func(io.Reader) error) (time.Duration, error) {
+	req, err := f.newRequest(ctx, rawURL)
+	if err != nil {
+		return -1, redactError(err, token)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	started := time.Now()
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		metrics.FetchDuration.ObserveDuration(started, "error")
+		metrics.FetchResponses.Inc("error")
+		// Network errors are transient, but once the overall timeout has passed there's no point retrying
+		if ctx.Err() != nil {
+			return -1, redactError(err, token)
+		}
+		return 0, redactError(err, token)
+	}
+	defer resp.Body.Close()
+	code := strconv.Itoa(resp.StatusCode)
+	metrics.FetchDuration.ObserveDuration(started, code)
+	metrics.FetchResponses.Inc(code)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("fetching %s: %s", redactURL(rawURL, token), resp.Status)
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), err
+		}
+		return -1, err
+	}
+
+	if err := parse(resp.Body); err != nil {
+		metrics.ParseFailures.Inc()
+		return -1, redactError(err, token)
+	}
+
+	return 0, nil
+}
+
+// redactionToken returns the token to keep out of errors and output when fetching rawURL
+func (f *Fetcher) redactionToken(rawURL string) string {
+	if f.Token != "" {
+		return f.Token
+	}
+	// A token passed in the URL itself must be kept out of errors and output too
+	return urlToken(rawURL)
+}
+
+// parseRetryAfter interprets a Retry-After header given in seconds or as an HTTP date, up to maxRetryBackoff
+// so a server can't stall a run indefinitely, returning zero if the header is absent or invalid
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(min(seconds, int(maxRetryBackoff/time.Second))) * time.Second
+	}
+
+	if when, err := http.ParseTime(header); err == nil && when.After(now) {
+		return min(when.Sub(now), maxRetryBackoff)
+	}
+
+	return 0
+}
+
+// newRequest builds the GET request for rawURL, adding the User-Agent and the token if one is configured
+func (f *Fetcher) newRequest(ctx context.Context, rawURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.UserAgent != "" {
+		req.Header.Set("User-Agent", f.UserAgent)
+	}
+
+	if f.Token == "" {
+		return req, nil
+	}
+
+	// Private Atom feeds (e.g., github.com/username.private.atom) only accept the token as a query parameter
+	if strings.HasSuffix(req.URL.Path, ".private.atom") {
+		query := req.URL.Query()
+		query.Set("token", f.Token)
+		req.URL.RawQuery = query.Encode()
+	}
+	req.Header.Set("Authorization", "token "+f.Token)
+
+	return req, nil
+}
+
+// urlToken returns the token query parameter embedded in a feed URL, if any
+func urlToken(feedURL string) string {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Query().Get("token")
+}
+
+// redactURL removes the token query parameter from link and masks any other occurrence of token
+func redactURL(link, token string) string {
+	if link == "" {
+		return link
+	}
+
+	if parsed, err := url.Parse(link); err == nil && parsed.Query().Has("token") {
+		query := parsed.Query()
+		query.Del("token")
+		parsed.RawQuery = query.Encode()
+		link = parsed.String()
+	}
+
+	if token != "" {
+		link = strings.ReplaceAll(link, token, redactedToken)
+	}
+	return link
+}
+
+// redactError masks token in err's message, including in the URL of a *url.Error
+func redactError(err error, token string) error {
+	if err == nil || token == "" {
+		return err
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(urlErr.URL, token)
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, token) {
+		return err
+	}
+	return errors.New(strings.ReplaceAll(msg, token, redactedToken))
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testToken = "s3cr3t-t0ken-value"
+
+// privateFeedXML is a minimal private Atom feed whose self link embeds the token, as GitHub's does
+func privateFeedXML(baseURL string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <id>tag:github.com,2008:/cdzombak</id>
+  <link type="text/html" rel="alternate" href="%[1]s/cdzombak"/>
+  <link type="application/atom+xml" rel="self" href="%[1]s/cdzombak.private.atom?token=%[2]s"/>
+  <title>Private Feed for cdzombak</title>
+  <updated>2025-09-15T01:28:02Z</updated>
+  <entry>
+    <id>tag:github.com,2008:PushEvent/1</id>
+    <published>2025-09-15T01:28:02Z</published>
+    <updated>2025-09-15T01:28:02Z</updated>
+    <link type="text/html" rel="alternate" href="%[1]s/cdzombak/dotfiles/compare/b19a1b604e...8e9b024bed"/>
+    <title type="html">cdzombak pushed dotfiles</title>
+    <content type="html">push</content>
+  </entry>
+</feed>`, baseURL, testToken)
+}
+
+func TestLoadToken(t *testing.T) {
+	dir := t.TempDir()
+
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte(testToken+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(tokenEnvVar, "env-token")
+
+	token, err := loadToken(tokenFile)
+	if err != nil || token != testToken {
+		t.Errorf("loadToken(file) = %q, %v, want %q", token, err, testToken)
+	}
+
+	token, err = loadToken("")
+	if err != nil || token != "env-token" {
+		t.Errorf("loadToken(\"\") = %q, %v, want env-token", token, err)
+	}
+
+	if _, err := loadToken(emptyFile); err == nil {
+		t.Errorf("loadToken(empty file) should fail")
+	}
+
+	if _, err := loadToken(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("loadToken(missing file) should fail")
+	}
+}
+
+func TestFetcherAddsToken(t *testing.T) {
+	var gotAuth, gotQueryToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotQueryToken = r.URL.Query().Get("token")
+		fmt.Fprint(w, privateFeedXML("http://"+r.Host))
+	}))
+	defer server.Close()
+
+	feed, err := newFetcher(testToken).fetchFeed(server.URL + "/cdzombak.private.atom")
+	if err != nil {
+		t.Fatalf("fetchFeed() error = %v", err)
+	}
+
+	if gotAuth != "token "+testToken {
+		t.Errorf("Authorization header = %q, want token auth", gotAuth)
+	}
+	if gotQueryToken != testToken {
+		t.Errorf("token query parameter = %q, want %q", gotQueryToken, testToken)
+	}
+
+	if strings.Contains(feed.FeedLink, testToken) || strings.Contains(feed.FeedLink, "token=") {
+		t.Errorf("fetchFeed().FeedLink leaks token: %v", feed.FeedLink)
+	}
+	for _, link := range feed.Links {
+		if strings.Contains(link, testToken) {
+			t.Errorf("fetchFeed().Links leaks token: %v", link)
+		}
+	}
+	if len(feed.Items) != 1 {
+		t.Errorf("fetchFeed() items count = %d, want 1", len(feed.Items))
+	}
+}
+
+func TestFetcherPublicFeedWithoutToken(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		if r.URL.Query().Has("token") {
+			t.Errorf("unexpected token query parameter")
+		}
+		fmt.Fprint(w, privateFeedXML("http://"+r.Host))
+	}))
+	defer server.Close()
+
+	if _, err := newFetcher("").fetchFeed(server.URL + "/cdzombak.atom"); err != nil {
+		t.Fatalf("fetchFeed() error = %v", err)
+	}
+	if gotAuth != "" {
+		t.Errorf("Authorization header = %q, want none", gotAuth)
+	}
+}
+
+func TestFetcherErrorsRedactToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// Token configured via file/env
+	_, err := newFetcher(testToken).fetchFeed(server.URL + "/cdzombak.private.atom")
+	if err == nil {
+		t.Fatal("fetchFeed() error = nil, want error")
+	}
+	if strings.Contains(err.Error(), testToken) {
+		t.Errorf("fetchFeed() error leaks token: %v", err)
+	}
+
+	// Token passed in the URL on the command line
+	_, err = newFetcher("").fetchFeed(server.URL + "/cdzombak.private.atom?token=" + testToken)
+	if err == nil {
+		t.Fatal("fetchFeed() error = nil, want error")
+	}
+	if strings.Contains(err.Error(), testToken) {
+		t.Errorf("fetchFeed() error leaks token: %v", err)
+	}
+
+	// Transport-level errors include the request URL
+	server.Close()
+	fetcher := newTestFetcher()
+	fetcher.Token = testToken
+	_, err = fetcher.fetchFeed(server.URL + "/cdzombak.private.atom")
+	if err == nil {
+		t.Fatal("fetchFeed() error = nil, want error")
+	}
+	if strings.Contains(err.Error(), testToken) {
+		t.Errorf("fetchFeed() transport error leaks token: %v", err)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		link     string
+		expected string
+	}{
+		{"https://github.com/cdzombak.private.atom?token=" + testToken, "https://github.com/cdzombak.private.atom"},
+		{"https://github.com/cdzombak", "https://github.com/cdzombak"},
+		{"https://github.com/x?a=" + testToken, "https://github.com/x?a=" + redactedToken},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if result := redactURL(tt.link, testToken); result != tt.expected {
+			t.Errorf("redactURL(%q) = %v, want %v", tt.link, result, tt.expected)
+		}
+	}
+}
+
+// newTestFetcher returns a Fetcher with short delays suitable for tests
+func newTestFetcher() *Fetcher {
+	fetcher := newFetcher("")
+	fetcher.RetryBackoff = time.Millisecond
+	fetcher.Timeout = 5 * time.Second
+	return fetcher
+}
+
+func TestFetcherUserAgent(t *testing.T) {
+	var gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		fmt.Fprint(w, privateFeedXML("http://"+r.Host))
+	}))
+	defer server.Close()
+
+	if _, err := newTestFetcher().fetchFeed(server.URL + "/cdzombak.atom"); err != nil {
+		t.Fatalf("fetchFeed() error = %v", err)
+	}
+	if gotUserAgent != "ghfeed/"+version {
+		t.Errorf("User-Agent = %q, want ghfeed/%s", gotUserAgent, version)
+	}
+}
+
+func TestFetcherRetriesTransientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch attempts.Add(1) {
+		case 1:
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		default:
+			fmt.Fprint(w, privateFeedXML("http://"+r.Host))
+		}
+	}))
+	defer server.Close()
+
+	feed, err := newTestFetcher().fetchFeed(server.URL + "/cdzombak.atom")
+	if err != nil {
+		t.Fatalf("fetchFeed() error = %v", err)
+	}
+	if len(feed.Items) != 1 {
+		t.Errorf("fetchFeed() items count = %d, want 1", len(feed.Items))
+	}
+	if n := attempts.Load(); n != 3 {
+		t.Errorf("fetchFeed() made %d attempts, want 3", n)
+	}
+}
+
+func TestFetcherGivesUpAfterMaxRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	fetcher := newTestFetcher()
+	fetcher.MaxRetries = 2
+	_, err := fetcher.fetchFeed(server.URL + "/cdzombak.atom")
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("fetchFeed() error = %v, want 503 error", err)
+	}
+	if n := attempts.Load(); n != 3 {
+		t.Errorf("fetchFeed() made %d attempts, want 3", n)
+	}
+}
+
+func TestFetcherDoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if _, err := newTestFetcher().fetchFeed(server.URL + "/cdzombak.atom"); err == nil {
+		t.Error("fetchFeed() error = nil, want error")
+	}
+	if n := attempts.Load(); n != 1 {
+		t.Errorf("fetchFeed() made %d attempts, want 1", n)
+	}
+}
+
+func TestFetcherTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	fetcher := newTestFetcher()
+	fetcher.Timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	_, err := fetcher.fetchFeed(server.URL + "/cdzombak.atom")
+	if err == nil {
+		t.Fatal("fetchFeed() error = nil, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("fetchFeed() took %v, want it bounded by the timeout", elapsed)
+	}
+}
+
+func TestFetcherRetryAfterBeyondTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	_, err := newTestFetcher().fetchFeed(server.URL + "/cdzombak.atom")
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("fetchFeed() error = %v, want 429 error", err)
+	}
+	if n := attempts.Load(); n != 1 {
+		t.Errorf("fetchFeed() made %d attempts, want 1", n)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("fetchFeed() waited %v despite Retry-After exceeding the timeout", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 9, 15, 1, 28, 2, 0, time.UTC)
+
+	tests := []struct {
+		header   string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"0", 0},
+		{"-5", 0},
+		{"Mon, 15 Sep 2025 01:29:02 GMT", time.Minute},
+		{"86400", maxRetryBackoff},
+		{"99999999999999", maxRetryBackoff},
+		{"Mon, 15 Sep 2125 01:29:02 GMT", maxRetryBackoff},
+		{"Mon, 15 Sep 2025 01:00:00 GMT", 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if result := parseRetryAfter(tt.header, now); result != tt.expected {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, result, tt.expected)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	fetcher := newFetcher("")
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{8, 256 * time.Second},
+		{9, maxRetryBackoff},
+		{100, maxRetryBackoff},
+	}
+
+	for _, tt := range tests {
+		if result := fetcher.retryBackoff(tt.attempt); result != tt.expected {
+			t.Errorf("retryBackoff(%d) = %v, want %v", tt.attempt, result, tt.expected)
+		}
+	}
+}
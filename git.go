@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// gitLogFormat is the git log format read by the git source: hash, the branch the commit was reached
+// from (with --source), author, committer date, and subject, separated by ASCII unit separators
+const gitLogFormat = "%H%x1f%S%x1f%an%x1f%cI%x1f%s"
+
+// gitMaxCommits limits how many of the most recent commits the git source reads
+const gitMaxCommits = 500
+
+// readGitFeed builds a consolidated feed from the branches of a local git repository, for mirrors and
+// air-gapped servers that have no forge feed. Each commit becomes a single-commit push to the branch it
+// was reached from, grouped like a commits feed. Only local branches are read, not remote-tracking refs
+// or tags, and a commit on several branches is listed once, under the first branch git log reached it
+// from. Bare mirrors usually have no reflog, so the feed is built from history rather than pushes.
+func readGitFeed(repoPath string, opts Options) (*gofeed.Feed, error) {
+	cmd := exec.Command("git", "-C", repoPath, "log", "--branches", "--source", "--date-order",
+		"-n", strconv.Itoa(gitMaxCommits), "--format="+gitLogFormat)
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git log: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	repo := gitRepoName(repoPath)
+	link := opts.LinkTemplate
+	if link == "" {
+		absPath, err := filepath.Abs(repoPath)
+		if err != nil {
+			return nil, err
+		}
+		link = "file://" + filepath.ToSlash(absPath)
+	} else {
+		link = gitRepoLink(link, repo)
+	}
+
+	feed := &gofeed.Feed{
+		Title: fmt.Sprintf("%s Activity", repo),
+		Link:  link,
+	}
+
+	if opts.PushWindow <= 0 {
+		opts.PushWindow = defaultCommitWindow
+	}
+	opts.Provider = providerGit
+
+	return consolidateActivities(feed, parseGitLog(string(output), repo, opts.LinkTemplate), repo, "", opts), nil
+}
+
+// parseGitLog turns git log output in gitLogFormat into single-commit pushes
+func parseGitLog(output, repo, linkTemplate string) []Activity {
+	activities := []Activity{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+		hash, ref, author, date, subject := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+		committedAt, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			continue
+		}
+		branch := strings.TrimPrefix(ref, "refs/heads/")
+		// Commit messages are HTML everywhere else
+		message := html.EscapeString(subject)
+
+		activities = append(activities, Activity{Push: &BranchActivity{
+			Actor:  author,
+			Repo:   repo,
+			Branch: branch,
+			Commits: []Commit{
+				{
+					Hash:    shortHash(hash, 7),
+					Message: message,
+					Link:    gitCommitLink(linkTemplate, repo, branch, hash),
+				},
+			},
+			LatestTime:   &committedAt,
+			TotalCommits: 1,
+		}})
+	}
+	return activities
+}
+
+// gitRepoName derives a repository's name from its path, dropping a bare repository's .git suffix
+func gitRepoName(repoPath string) string {
+	name := filepath.Base(filepath.Clean(repoPath))
+	if name == ".git" {
+		name = filepath.Base(filepath.Dir(filepath.Clean(repoPath)))
+	}
+	return strings.TrimSuffix(name, ".git")
+}
+
+// gitCommitLink fills in a link template's {repo}, {branch}, and {hash} placeholders for a commit
+// (e.g., https://git.example.com/mirrors/{repo}/commit/{hash}), returning "" when there's no template
+func gitCommitLink(linkTemplate, repo, branch, hash string) string {
+	if linkTemplate == "" {
+		return ""
+	}
+	return strings.NewReplacer("{repo}", repo, "{branch}", branch, "{hash}", hash).Replace(linkTemplate)
+}
+
+// gitRepoLink derives the repository's web link from a commit link template, keeping everything before
+// its {hash} or {branch} placeholder or query string, less any trailing commit path segment
+func gitRepoLink(linkTemplate, repo string) string {
+	link := strings.ReplaceAll(linkTemplate, "{repo}", repo)
+	if i := strings.IndexAny(link, "{?"); i >= 0 {
+		link = link[:i]
+	}
+	link = strings.TrimSuffix(link, "/")
+	for _, suffix := range []string{"/commit", "/commits", "/-"} {
+		link = strings.TrimSuffix(link, suffix)
+	}
+	return link
+}
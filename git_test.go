@@ -0,0 +1,147 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// newTestRepo creates a git repository with commits on main and a feature branch
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	commit := func(author, date, message string) {
+		t.Helper()
+		cmd := exec.Command("git", "-C", dir, "commit", "--allow-empty", "-q", "-m", message)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+author, "GIT_AUTHOR_EMAIL=dev@example.com", "GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME="+author, "GIT_COMMITTER_EMAIL=dev@example.com", "GIT_COMMITTER_DATE="+date,
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git commit: %v: %s", err, out)
+		}
+	}
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	git("init", "-q", "-b", "main")
+	commit("Chris Dzombak", "2025-09-14T20:00:00Z", "Initial commit")
+	commit("Chris Dzombak", "2025-09-14T20:10:00Z", "Add README")
+	git("checkout", "-q", "-b", "feature")
+	commit("Jane Doe", "2025-09-14T21:00:00Z", "Start feature")
+	git("checkout", "-q", "main")
+	commit("Chris Dzombak", "2025-09-15T01:28:02Z", "Fix build")
+	// A commit reachable only from a tag
+	git("checkout", "-q", "--detach")
+	commit("Chris Dzombak", "2025-09-15T02:00:00Z", "Tagged release")
+	git("tag", "v1.0.0")
+	git("checkout", "-q", "main")
+
+	return dir
+}
+
+func TestReadGitFeed(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := newTestRepo(t)
+
+	result, err := readGitFeed(dir, Options{
+		ConsolidatePushes: true,
+		LinkTemplate:      "https://git.example.com/mirrors/{repo}/commit/{hash}",
+	})
+	if err != nil {
+		t.Fatalf("readGitFeed() error = %v", err)
+	}
+
+	repo := gitRepoName(dir)
+	if result.Link != "https://git.example.com/mirrors/"+repo {
+		t.Errorf("readGitFeed().Link = %v", result.Link)
+	}
+
+	// Tags aren't read, and the commits main and feature share are listed once, under main
+	expectedTitles := []string{
+		"Chris Dzombak pushed 1 commit to " + repo + "/main",
+		"Jane Doe pushed 1 commit to " + repo + "/feature",
+		"Chris Dzombak pushed 2 commits to " + repo + "/main",
+	}
+	if len(result.Items) != len(expectedTitles) {
+		t.Fatalf("readGitFeed() items count = %d, want %d", len(result.Items), len(expectedTitles))
+	}
+	for i, expected := range expectedTitles {
+		if result.Items[i].Title != expected {
+			t.Errorf("readGitFeed().Items[%d].Title = %v, want %v", i, result.Items[i].Title, expected)
+		}
+	}
+
+	// Local repositories have no compare view, so pushes link to their newest commit
+	commitPrefix := "https://git.example.com/mirrors/" + repo + "/commit/"
+	if link := result.Items[2].Link; !strings.HasPrefix(link, commitPrefix) || len(link) != len(commitPrefix)+40 {
+		t.Errorf("readGitFeed() push link = %v, want a link to the newest commit", link)
+	}
+	if strings.Contains(result.Items[2].Content, "View all changes") {
+		t.Errorf("readGitFeed() push content = %v, want no compare link", result.Items[2].Content)
+	}
+	for _, item := range result.Items {
+		if strings.Contains(item.Content, "Tagged release") {
+			t.Errorf("readGitFeed() included a commit only reachable from a tag: %v", item.Title)
+		}
+	}
+}
+
+func TestParseGitLog(t *testing.T) {
+	output := "8e9b024bedc0ffee\x1frefs/heads/main\x1fChris Dzombak\x1f2025-09-15T01:28:02Z\x1fUse <fzf> & fd\n"
+	activities := parseGitLog(output, "dotfiles", "")
+	if len(activities) != 1 || activities[0].Push == nil {
+		t.Fatalf("parseGitLog() = %v, want one push", activities)
+	}
+
+	commit := activities[0].Push.Commits[0]
+	if commit.Message != "Use &lt;fzf&gt; &amp; fd" {
+		t.Errorf("parseGitLog() message = %q, want it escaped as HTML", commit.Message)
+	}
+	if commit.Link != "" {
+		t.Errorf("parseGitLog() link = %q, want none without a link template", commit.Link)
+	}
+
+	// Without links, hashes are rendered as plain text
+	feed := consolidateActivities(&gofeed.Feed{}, activities, "Chris Dzombak", "", Options{ConsolidatePushes: true})
+	if content := feed.Items[0].Content; !strings.Contains(content, "<tt>8e9b024</tt>: Use &lt;fzf&gt; &amp; fd") || strings.Contains(content, "href=''") {
+		t.Errorf("git push content = %v, want an unlinked hash", content)
+	}
+}
+
+func TestReadGitFeedNotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	if _, err := readGitFeed(t.TempDir(), Options{}); err == nil {
+		t.Error("readGitFeed() error = nil, want error for a directory that isn't a repository")
+	}
+}
+
+func TestGitRepoLink(t *testing.T) {
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"https://git.example.com/mirrors/{repo}/commit/{hash}", "https://git.example.com/mirrors/ghfeed"},
+		{"https://gitlab.example.com/group/{repo}/-/commit/{hash}", "https://gitlab.example.com/group/ghfeed"},
+		{"https://cgit.example.com/{repo}.git/commit/?id={hash}", "https://cgit.example.com/ghfeed.git"},
+	}
+
+	for _, tt := range tests {
+		if result := gitRepoLink(tt.template, "ghfeed"); result != tt.expected {
+			t.Errorf("gitRepoLink(%q) = %v, want %v", tt.template, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// extractGiteaActivities classifies each item in a Gitea or Forgejo activity feed (e.g.,
+// https://gitea.example.com/username.rss), extracting pushes and simplifying everything else.
+// Gitea item titles are HTML, such as `user pushed to <a href="...">main</a> at <a href="...">owner/repo</a>`.
+func extractGiteaActivities(feed *gofeed.Feed, username, host string) []Activity {
+	activities := []Activity{}
+	for _, item := range feed.Items {
+		text := htmlText(item.Title)
+		if push := extractGiteaPush(item, text, host); push != nil {
+			push.Sources = []*gofeed.Item{item}
+			activities = append(activities, Activity{Push: push})
+			continue
+		}
+		activities = append(activities, Activity{Item: simplifyGiteaItem(item, text, username, host), Sources: []*gofeed.Item{item}})
+	}
+	return activities
+}
+
+// extractGiteaPush extracts repository, branch, and commit data from a Gitea push item, returning nil if
+// the item isn't a push
+func extractGiteaPush(item *gofeed.Item, text, host string) *BranchActivity {
+	pushRegex := regexp.MustCompile(`pushed to (\S+) at ([^/\s]+)/([^/\s]+)$`)
+	matches := pushRegex.FindStringSubmatch(text)
+	if matches == nil {
+		return nil
+	}
+
+	// Each commit is a link to it, its full hash as the link text, then the rendered message:
+	// <a href="https://gitea.example.com/owner/repo/commit/sha">sha</a>\nmessage
+	var commits []Commit
+	commitRegex := regexp.MustCompile(`(?sm)^\s*<a href="([^"]*/commit/([0-9a-f]{7,40}))">[^<]*</a>\s*(.*?)(?:\n\s*\n|\z)`)
+	for _, match := range commitRegex.FindAllStringSubmatch(itemBody(item), -1) {
+		message := htmlText(firstLine(match[3]))
+		if message == "" {
+			message = "Commit " + shortHash(match[2], 7)
+		}
+		commits = append(commits, Commit{
+			Hash:    shortHash(match[2], 7),
+			Message: html.EscapeString(message),
+			Link:    absoluteLink(match[1], host),
+		})
+	}
+
+	latestTime := item.PublishedParsed
+	if latestTime == nil {
+		latestTime = item.UpdatedParsed
+	}
+
+	// Gitea lists a push's commits newest-first, so no reordering is needed
+	return &BranchActivity{
+		Owner:        matches[2],
+		Repo:         matches[3],
+		Branch:       matches[1],
+		Commits:      commits,
+		LatestTime:   latestTime,
+		CompareLink:  item.Link,
+		TotalCommits: len(commits),
+	}
+}
+
+// simplifyGiteaItem creates a simplified version of a non-push Gitea activity
+func simplifyGiteaItem(item *gofeed.Item, text, username, host string) *gofeed.Item {
+	prRegex := regexp.MustCompile(`(created|merged|closed|reopened) pull request ([^/\s]+/[^/\s#]+)#(\d+)$`)
+	if matches := prRegex.FindStringSubmatch(text); matches != nil {
+		action := matches[1]
+		if action == "created" {
+			action = "opened"
+		}
+		// The description holds the pull request's index and title, e.g. "12#Fix the thing"
+		prTitle := regexp.MustCompile(`^\d+#`).ReplaceAllString(htmlText(itemBody(item)), "")
+		return createPullRequestItem(item, username, action, matches[3], matches[2], html.EscapeString(prTitle), "")
+	}
+
+	forkRegex := regexp.MustCompile(`forked repository ([^/\s]+/[^/\s]+) to ([^/\s]+/[^/\s]+)$`)
+	if matches := forkRegex.FindStringSubmatch(text); matches != nil {
+		return createForkItem(item, username, matches[1], matches[2])
+	}
+
+	branchCreateRegex := regexp.MustCompile(`created branch (\S+) in ([^/\s]+/[^/\s]+)$`)
+	if matches := branchCreateRegex.FindStringSubmatch(text); matches != nil {
+		return createBranchCreateItem(item, username, html.EscapeString(matches[1]), matches[2])
+	}
+
+	if strings.Contains(text, "deleted branch ") {
+		return simplifyBranchDelete(item, username)
+	}
+
+	tagDeleteRegex := regexp.MustCompile(`deleted tag (\S+) from ([^/\s]+)/([^/\s]+)$`)
+	if matches := tagDeleteRegex.FindStringSubmatch(text); matches != nil {
+		link := fmt.Sprintf("%s/%s/%s", hostURL(host), matches[2], matches[3])
+		return createTagDeleteItem(item, username, html.EscapeString(matches[1]), matches[3], link)
+	}
+
+	// For other activities, keep the title's text
+	plain := *item
+	plain.Title = text
+	return simplifyOtherActivity(&plain, username)
+}
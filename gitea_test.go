@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestGiteaFeed(t *testing.T) {
+	feed := parseTestFeed(t, "gitea.rss")
+	result := consolidateCommits(feed, Options{ConsolidatePushes: true, Provider: providerGitea})
+
+	expectedTitles := []string{
+		"cdzombak pushed 3 commits to dotfiles/main",
+		"cdzombak opened PR #12 in infra/ansible: Add a role for the backup server",
+		"cdzombak created branch cdz/backup in infra/ansible",
+		"cdzombak deleted tag v0.0.6 in homebrew-gomod",
+		"cdzombak starred infra/ansible",
+	}
+	if len(result.Items) != len(expectedTitles) {
+		t.Fatalf("consolidateCommits() items count = %d, want %d", len(result.Items), len(expectedTitles))
+	}
+	for i, expected := range expectedTitles {
+		if result.Items[i].Title != expected {
+			t.Errorf("consolidateCommits().Items[%d].Title = %v, want %v", i, result.Items[i].Title, expected)
+		}
+	}
+
+	push := result.Items[0]
+	expectedLink := "https://git.example.com/cdzombak/dotfiles/compare/5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b^...b19a1b604e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b"
+	if push.Link != expectedLink {
+		t.Errorf("push link = %v, want %v", push.Link, expectedLink)
+	}
+	expectedCommit := "<tt><a href='https://git.example.com/cdzombak/dotfiles/commit/b19a1b604e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b'>b19a1b6</a></tt>: zsh: fix prompt on #3"
+	if !strings.Contains(push.Content, expectedCommit) {
+		t.Errorf("push content missing %q, got %v", expectedCommit, push.Content)
+	}
+
+	if tagDelete := result.Items[3]; tagDelete.Link != "https://git.example.com/cdzombak/homebrew-gomod" {
+		t.Errorf("tag delete link = %v", tagDelete.Link)
+	}
+}
+
+// TestGiteaEscapesText checks that text decoded from Gitea's HTML is escaped again before it reaches item HTML
+func TestGiteaEscapesText(t *testing.T) {
+	pushed := time.Date(2025, 9, 15, 2, 0, 0, 0, time.UTC)
+	opened := time.Date(2025, 9, 15, 1, 0, 0, 0, time.UTC)
+	feed := &gofeed.Feed{
+		Link: "https://git.example.com/cdzombak",
+		Items: []*gofeed.Item{
+			{
+				Title:           `cdzombak pushed to <a href="https://git.example.com/cdzombak/dotfiles/src/branch/main">main</a> at <a href="https://git.example.com/cdzombak/dotfiles">cdzombak/dotfiles</a>`,
+				Link:            "https://git.example.com/cdzombak/dotfiles/commit/b19a1b604e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b",
+				Description:     "<a href=\"https://git.example.com/cdzombak/dotfiles/commit/b19a1b604e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b\">b19a1b604e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b</a>\n&lt;img src=x onerror=alert(1)&gt; &amp; more",
+				PublishedParsed: &pushed,
+			},
+			{
+				Title:           `cdzombak created pull request <a href="https://git.example.com/infra/ansible/pulls/12">infra/ansible#12</a>`,
+				Link:            "https://git.example.com/infra/ansible/pulls/12",
+				Description:     "12#Use &lt;fzf&gt; &amp; fd",
+				PublishedParsed: &opened,
+			},
+		},
+	}
+	result := consolidateCommits(feed, Options{ConsolidatePushes: true, Provider: providerGitea})
+
+	expected := []string{
+		"&lt;img src=x onerror=alert(1)&gt; &amp; more",
+		"Use &lt;fzf&gt; &amp; fd",
+	}
+	if len(result.Items) != len(expected) {
+		t.Fatalf("consolidateCommits() items count = %d, want %d", len(result.Items), len(expected))
+	}
+	for i, want := range expected {
+		content := result.Items[i].Content
+		if !strings.Contains(content, want) || strings.Contains(content, "<img") || strings.Contains(content, "<fzf>") {
+			t.Errorf("item %d content = %v, want it to contain %v", i, content, want)
+		}
+	}
+}
+
+func TestNormalizeProvider(t *testing.T) {
+	tests := map[string]string{
+		"":          providerGitHub,
+		"github":    providerGitHub,
+		"Gitea":     providerGitea,
+		"forgejo":   providerGitea,
+		"gitlab":    providerGitLab,
+		"bitbucket": "",
+	}
+
+	for name, expected := range tests {
+		if result := normalizeProvider(name); result != expected {
+			t.Errorf("normalizeProvider(%q) = %v, want %v", name, result, expected)
+		}
+	}
+}
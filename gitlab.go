@@ -0,0 +1,142 @@
+package main
+
+import (
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// extractGitLabActivities classifies each item in a GitLab activity feed (e.g., https://gitlab.com/username.atom),
+// extracting pushes and simplifying everything else.
+// GitLab item titles are plain text, such as "Jane Doe pushed to branch main at Jane Doe / project".
+func extractGitLabActivities(feed *gofeed.Feed, username, host string) []Activity {
+	activities := []Activity{}
+	for _, item := range feed.Items {
+		if push := extractGitLabPush(item, host); push != nil {
+			push.Sources = []*gofeed.Item{item}
+			activities = append(activities, Activity{Push: push})
+			continue
+		}
+		activities = append(activities, Activity{Item: simplifyGitLabItem(item, username, host), Sources: []*gofeed.Item{item}})
+	}
+	return activities
+}
+
+// extractGitLabPush extracts project, branch, and commit data from a GitLab push item, returning nil if
+// the item isn't a push to a branch
+func extractGitLabPush(item *gofeed.Item, host string) *BranchActivity {
+	pushRegex := regexp.MustCompile(`pushed to branch (\S+) at `)
+	matches := pushRegex.FindStringSubmatch(item.Title)
+	if matches == nil {
+		return nil
+	}
+
+	projectPath := gitlabProjectPath(item.Link)
+	if projectPath == "" {
+		return nil
+	}
+
+	// Each commit is a "(#sha)" link followed by its rendered message in a blockquote
+	var commits []Commit
+	body := itemBody(item)
+	commitRegex := regexp.MustCompile(`(?s)<a[^>]*href="([^"]*/-/commit/([0-9a-f]+))"[^>]*>\s*\(#([0-9a-f]+)\)\s*</a>.*?<blockquote[^>]*>(.*?)</blockquote>`)
+	for _, match := range commitRegex.FindAllStringSubmatch(body, -1) {
+		message := htmlText(firstLine(strings.ReplaceAll(match[4], "</p>", "\n")))
+		if message == "" {
+			message = "Commit " + shortHash(match[2], 7)
+		}
+		commits = append(commits, Commit{
+			Hash:    shortHash(match[2], 7),
+			Message: html.EscapeString(message),
+			Link:    absoluteLink(match[1], host),
+		})
+	}
+
+	// GitLab lists a push's commits oldest-first; make them newest-first
+	slices.Reverse(commits)
+
+	// GitLab lists only the first 15 commits of a large push, followed by "... and N more commits"
+	moreCount := 0
+	moreLink := ""
+	moreRegex := regexp.MustCompile(`and\s+([\d,]+)\s+more commits?`)
+	if more := moreRegex.FindStringSubmatch(htmlText(body)); more != nil {
+		moreCount, _ = strconv.Atoi(strings.ReplaceAll(more[1], ",", ""))
+		if strings.Contains(item.Link, "/-/compare/") {
+			moreLink = item.Link
+		}
+	}
+
+	latestTime := item.PublishedParsed
+	if latestTime == nil {
+		latestTime = item.UpdatedParsed
+	}
+
+	return &BranchActivity{
+		Owner:           path.Dir(projectPath),
+		Repo:            path.Base(projectPath),
+		Branch:          matches[1],
+		Commits:         commits,
+		LatestTime:      latestTime,
+		CompareLink:     item.Link,
+		TotalCommits:    len(commits) + moreCount,
+		MoreCommitsLink: moreLink,
+	}
+}
+
+// simplifyGitLabItem creates a simplified version of a non-push GitLab activity
+func simplifyGitLabItem(item *gofeed.Item, username, host string) *gofeed.Item {
+	projectPath := gitlabProjectPath(item.Link)
+
+	mrRegex := regexp.MustCompile(`(opened|accepted|closed|reopened) merge request !(\d+): (.*) at `)
+	if matches := mrRegex.FindStringSubmatch(item.Title); matches != nil {
+		action := matches[1]
+		if action == "accepted" {
+			action = "merged"
+		}
+		return createPullRequestItem(item, username, action, matches[2], projectPath, html.EscapeString(strings.TrimSpace(matches[3])), "")
+	}
+
+	branchCreateRegex := regexp.MustCompile(`pushed new branch (\S+) at `)
+	if matches := branchCreateRegex.FindStringSubmatch(item.Title); matches != nil {
+		return createBranchCreateItem(item, username, html.EscapeString(matches[1]), projectPath)
+	}
+
+	if strings.Contains(item.Title, " deleted branch ") {
+		return simplifyBranchDelete(item, username)
+	}
+
+	tagDeleteRegex := regexp.MustCompile(`deleted tag (\S+) at `)
+	if matches := tagDeleteRegex.FindStringSubmatch(item.Title); matches != nil {
+		repoName := ""
+		link := item.Link
+		if projectPath != "" {
+			repoName = path.Base(projectPath)
+			link = hostURL(host) + "/" + projectPath
+		}
+		return createTagDeleteItem(item, username, html.EscapeString(matches[1]), repoName, link)
+	}
+
+	return simplifyOtherActivity(item, username)
+}
+
+// gitlabProjectPath extracts a project's full path, including any subgroups, from a GitLab web URL
+// (e.g., https://gitlab.com/group/subgroup/project/-/compare/a...b gives group/subgroup/project)
+func gitlabProjectPath(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	projectPath, _, _ := strings.Cut(parsed.Path, "/-/")
+	projectPath = strings.Trim(projectPath, "/")
+	if !strings.Contains(projectPath, "/") {
+		return ""
+	}
+	return projectPath
+}
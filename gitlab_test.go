@@ -0,0 +1,115 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestGitLabFeed(t *testing.T) {
+	feed := parseTestFeed(t, "gitlab.atom")
+	result := consolidateCommits(feed, Options{ConsolidatePushes: true, Provider: providerGitLab})
+
+	expectedTitles := []string{
+		"cdzombak pushed 5 commits to cdzombak/tools/ghfeed/main",
+		"cdzombak merged PR #7 in cdzombak/tools/ghfeed: Look at feeds at work",
+		"cdzombak created branch cdz/gitlab in cdzombak/tools/ghfeed",
+		"cdzombak deleted tag v0.0.6 in homebrew-gomod",
+		"Chris Dzombak opened issue #3: Support Forgejo at Chris Dzombak / tools / ghfeed",
+	}
+	if len(result.Items) != len(expectedTitles) {
+		t.Fatalf("consolidateCommits() items count = %d, want %d", len(result.Items), len(expectedTitles))
+	}
+	for i, expected := range expectedTitles {
+		if result.Items[i].Title != expected {
+			t.Errorf("consolidateCommits().Items[%d].Title = %v, want %v", i, result.Items[i].Title, expected)
+		}
+	}
+
+	// The truncated push should link to GitLab's compare view for the whole push
+	push := result.Items[0]
+	expectedLink := "https://gitlab.com/cdzombak/tools/ghfeed/-/compare/3f1e2d4c...b19a1b60"
+	if push.Link != expectedLink {
+		t.Errorf("push link = %v, want %v", push.Link, expectedLink)
+	}
+	for _, expected := range []string{
+		"<tt><a href='https://gitlab.com/cdzombak/tools/ghfeed/-/commit/b19a1b604e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b'>b19a1b6</a></tt>: Fix merge request titles",
+		"3 more commits",
+	} {
+		if !strings.Contains(push.Content, expected) {
+			t.Errorf("push content missing %q, got %v", expected, push.Content)
+		}
+	}
+}
+
+// TestGitLabEscapesText checks that GitLab's plain text titles and decoded commit messages are escaped before
+// they reach item HTML
+func TestGitLabEscapesText(t *testing.T) {
+	pushed := time.Date(2025, 9, 15, 2, 0, 0, 0, time.UTC)
+	opened := time.Date(2025, 9, 15, 1, 0, 0, 0, time.UTC)
+	feed := &gofeed.Feed{
+		Link: "https://gitlab.com/cdzombak",
+		Items: []*gofeed.Item{
+			{
+				Title:           "Chris Dzombak pushed to branch main at Chris Dzombak / tools / ghfeed",
+				Link:            "https://gitlab.com/cdzombak/tools/ghfeed/-/compare/3f1e2d4c...b19a1b60",
+				Content:         `<p><a href="/cdzombak/tools/ghfeed/-/commit/b19a1b604e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b">(#b19a1b60)</a></p><blockquote><p dir="auto">&lt;img src=x onerror=alert(1)&gt; &amp; more</p></blockquote>`,
+				PublishedParsed: &pushed,
+			},
+			{
+				Title:           "Chris Dzombak opened merge request !7: Use <fzf> & fd at Chris Dzombak / tools / ghfeed",
+				Link:            "https://gitlab.com/cdzombak/tools/ghfeed/-/merge_requests/7",
+				PublishedParsed: &opened,
+			},
+		},
+	}
+	result := consolidateCommits(feed, Options{ConsolidatePushes: true, Provider: providerGitLab})
+
+	expected := []string{
+		"&lt;img src=x onerror=alert(1)&gt; &amp; more",
+		"Use &lt;fzf&gt; &amp; fd",
+	}
+	if len(result.Items) != len(expected) {
+		t.Fatalf("consolidateCommits() items count = %d, want %d", len(result.Items), len(expected))
+	}
+	for i, want := range expected {
+		content := result.Items[i].Content
+		if !strings.Contains(content, want) || strings.Contains(content, "<img") || strings.Contains(content, "<fzf>") {
+			t.Errorf("item %d content = %v, want it to contain %v", i, content, want)
+		}
+	}
+}
+
+func TestGitLabComparisonLink(t *testing.T) {
+	activity := &BranchActivity{
+		Owner:  "cdzombak/tools",
+		Repo:   "ghfeed",
+		Branch: "main",
+		Commits: []Commit{
+			{Hash: "b19a1b6", Link: "https://gitlab.com/cdzombak/tools/ghfeed/-/commit/b19a1b604e"},
+			{Hash: "8e9b024", Link: "https://gitlab.com/cdzombak/tools/ghfeed/-/commit/8e9b0241a2"},
+		},
+	}
+
+	expected := "https://gitlab.com/cdzombak/tools/ghfeed/-/compare/8e9b0241a2^...b19a1b604e"
+	if link := generateComparisonLink(activity, "cdzombak", "gitlab.com", providerGitLab); link != expected {
+		t.Errorf("generateComparisonLink() = %v, want %v", link, expected)
+	}
+}
+
+func TestGitLabProjectPath(t *testing.T) {
+	tests := map[string]string{
+		"https://gitlab.com/cdzombak/ghfeed/-/compare/a...b":           "cdzombak/ghfeed",
+		"https://gitlab.com/group/subgroup/project/-/merge_requests/7": "group/subgroup/project",
+		"https://gitlab.com/cdzombak/homebrew-gomod":                   "cdzombak/homebrew-gomod",
+		"https://gitlab.com/cdzombak":                                  "",
+	}
+
+	for link, expected := range tests {
+		if result := gitlabProjectPath(link); result != expected {
+			t.Errorf("gitlabProjectPath(%q) = %v, want %v", link, result, expected)
+		}
+	}
+}
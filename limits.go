@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// parseTimeBound parses a -since or -until value: an absolute date (2006-01-02) or time (RFC 3339),
+// or a duration like 72h meaning that long before now
+func parseTimeBound(value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		if duration < 0 {
+			return time.Time{}, fmt.Errorf("duration must not be negative: %s", value)
+		}
+		return now.Add(-duration), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("not a date, time, or duration: %s", value)
+}
+
+// filterDateRange keeps activities from since (inclusive) until until (exclusive); a zero bound is open.
+// It runs before pushes are merged, so consolidated items only count and compare the pushes in range.
+// Activities whose time is unknown are kept.
+func filterDateRange(activities []Activity, since, until time.Time) []Activity {
+	if since.IsZero() && until.IsZero() {
+		return activities
+	}
+
+	kept := []Activity{}
+	for _, activity := range activities {
+		at := activityTime(activity)
+		if !at.IsZero() && (!since.IsZero() && at.Before(since) || !until.IsZero() && !at.Before(until)) {
+			logger.Debug("dropped activity outside date range", append(activityAttrs(activity, ""), "time", at)...)
+			continue
+		}
+		kept = append(kept, activity)
+	}
+	return kept
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2025, 9, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"72h", time.Date(2025, 9, 12, 12, 0, 0, 0, time.UTC)},
+		{"30m", time.Date(2025, 9, 15, 11, 30, 0, 0, time.UTC)},
+		{"2025-09-01T08:00:00Z", time.Date(2025, 9, 1, 8, 0, 0, 0, time.UTC)},
+		{"2025-09-01", time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		result, err := parseTimeBound(tt.value, now)
+		if err != nil {
+			t.Errorf("parseTimeBound(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !result.Equal(tt.expected) {
+			t.Errorf("parseTimeBound(%q) = %v, want %v", tt.value, result, tt.expected)
+		}
+	}
+
+	for _, value := range []string{"yesterday", "-2h", "2025-13-01"} {
+		if _, err := parseTimeBound(value, now); err == nil {
+			t.Errorf("parseTimeBound(%q) error = nil, want error", value)
+		}
+	}
+}
+
+func TestDateRangeAndMaxItems(t *testing.T) {
+	at := func(hour int) *time.Time {
+		t := time.Date(2025, 9, 15, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	push := func(hash string, hour int) Activity {
+		link := "https://github.com/cdzombak/ghfeed/commit/" + hash
+		return Activity{Push: &BranchActivity{
+			Repo:         "ghfeed",
+			Branch:       "main",
+			Commits:      []Commit{{Hash: hash, Message: "Commit " + hash, Link: link}},
+			LatestTime:   at(hour),
+			CompareLink:  link,
+			TotalCommits: 1,
+		}}
+	}
+	activities := func() []Activity {
+		return []Activity{
+			push("ccccccc", 9),
+			push("bbbbbbb", 8),
+			push("aaaaaaa", 7),
+			{Item: &gofeed.Item{Title: "cdzombak starred golang/go", PublishedParsed: at(10)}},
+			{Item: &gofeed.Item{Title: "cdzombak forked mmcdole/gofeed", PublishedParsed: at(6)}},
+		}
+	}
+	feed := &gofeed.Feed{Title: "cdzombak's Activity"}
+
+	// Pushes before -since are left out of the consolidated item's count and compare link
+	result := consolidateActivities(feed, activities(), "cdzombak", "github.com", Options{
+		ConsolidatePushes: true,
+		Since:             *at(8),
+		Until:             *at(10),
+	})
+	if len(result.Items) != 1 {
+		t.Fatalf("consolidateActivities() items count = %d, want 1", len(result.Items))
+	}
+	if result.Items[0].Title != "cdzombak pushed 2 commits to ghfeed/main" {
+		t.Errorf("consolidateActivities().Items[0].Title = %v", result.Items[0].Title)
+	}
+	expectedLink := "https://github.com/cdzombak/ghfeed/compare/bbbbbbb^...ccccccc"
+	if result.Items[0].Link != expectedLink {
+		t.Errorf("consolidateActivities().Items[0].Link = %v, want %v", result.Items[0].Link, expectedLink)
+	}
+
+	// -max-items keeps the most recent items after sorting
+	result = consolidateActivities(feed, activities(), "cdzombak", "github.com", Options{
+		ConsolidatePushes: false,
+		MaxItems:          2,
+	})
+	if len(result.Items) != 2 {
+		t.Fatalf("consolidateActivities() items count = %d, want 2", len(result.Items))
+	}
+	if result.Items[0].Title != "cdzombak starred golang/go" || result.Items[1].Title != "cdzombak pushed 1 commit to ghfeed/main" {
+		t.Errorf("consolidateActivities() items = %v, %v", result.Items[0].Title, result.Items[1].Title)
+	}
+}
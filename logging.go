@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// logger receives diagnostics on stderr: warnings by default, and with -verbose, how each upstream item
+// was classified and parsed and why items were dropped
+var logger = newLogger(os.Stderr, slog.LevelWarn, "text")
+
+// newLogger creates a logger writing records at or above level to w, as "text" (logfmt) or "json"
+func newLogger(w io.Writer, level slog.Level, format string) *slog.Logger {
+	options := &slog.HandlerOptions{Level: level}
+	if format == "json" {
+		return slog.New(slog.NewJSONHandler(w, options))
+	}
+	return slog.New(slog.NewTextHandler(w, options))
+}
+
+// parseLogLevel parses a -log-level value: debug, info, warn, or error
+func parseLogLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(value) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("must be 'debug', 'info', 'warn', or 'error': %s", value)
+	}
+}
+
+// activityAttrs identifies an activity in log records: a push by repository and branch, anything else by title.
+// username may be empty when the caller doesn't know it.
+func activityAttrs(activity Activity, username string) []any {
+	if push := activity.Push; push != nil {
+		repo := push.Repo
+		if push.Owner != "" || username != "" {
+			repo = activityRepo(activity, username)
+		}
+		return []any{"repo", repo, "branch", push.Branch, "commits", commitCount(push)}
+	}
+	return []any{"title", activity.Item.Title, "link", activity.Item.Link}
+}
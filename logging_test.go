@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected slog.Level
+		wantErr  bool
+	}{
+		{"debug", slog.LevelDebug, false},
+		{"info", slog.LevelInfo, false},
+		{"warn", slog.LevelWarn, false},
+		{"WARNING", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"trace", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseLogLevel(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseLogLevel(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("parseLogLevel(%q) = %v, want %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+// captureLogs sends log records at level and above to a buffer, as JSON, until the test ends
+func captureLogs(t *testing.T, level slog.Level) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := logger
+	logger = newLogger(&buf, level, "json")
+	t.Cleanup(func() { logger = previous })
+	return &buf
+}
+
+// logRecords decodes captured JSON log records
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("log line isn't JSON: %q", line)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestVerboseLogging(t *testing.T) {
+	buf := captureLogs(t, slog.LevelDebug)
+	published := time.Date(2025, 9, 15, 1, 28, 2, 0, time.UTC)
+
+	feed := &gofeed.Feed{
+		Link: "https://github.com/cdzombak",
+		Items: []*gofeed.Item{
+			{
+				Title:           "cdzombak pushed dotfiles",
+				Link:            "https://github.com/cdzombak/dotfiles/compare/b19a1b604e...8e9b024bed",
+				Content:         `<a class="branch-name" href="/cdzombak/dotfiles/tree/main">main</a> <a href="/cdzombak/dotfiles/commit/8e9b024bed">8e9b024</a>`,
+				PublishedParsed: &published,
+			},
+			{
+				Title:   "cdzombak pushed to a repository they no longer own",
+				Link:    "https://github.com/someone-else",
+				Content: "",
+			},
+			{
+				Title: "cdzombak made cdzombak/ghfeed public",
+				Link:  "https://github.com/cdzombak/ghfeed",
+			},
+		},
+	}
+	consolidateCommits(feed, Options{ConsolidatePushes: true})
+
+	wantMessages := map[string]map[string]any{
+		"extracted commits":       {"method": "commit links (no messages)"},
+		"classified item as push": {"repo": "dotfiles", "branch": "main"},
+		"push item has no repository link; simplifying it as other activity": {"link": "https://github.com/someone-else"},
+		"unrecognized activity; using generic item":                          {"title": "cdzombak made cdzombak/ghfeed public"},
+	}
+	records := logRecords(t, buf)
+	for message, attrs := range wantMessages {
+		found := false
+		for _, record := range records {
+			if record["msg"] != message {
+				continue
+			}
+			matches := true
+			for key, value := range attrs {
+				if record[key] != value {
+					matches = false
+				}
+			}
+			if matches {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("no %q record with %v in logs:\n%s", message, attrs, buf.String())
+		}
+	}
+}
+
+func TestLoggingDroppedItems(t *testing.T) {
+	buf := captureLogs(t, slog.LevelDebug)
+
+	push := &BranchActivity{Repo: "dotfiles", Branch: "main"}
+	activities := []Activity{{Push: push}}
+	consolidateActivities(&gofeed.Feed{}, activities, "cdzombak", "github.com", Options{ConsolidatePushes: true})
+
+	records := logRecords(t, buf)
+	if len(records) != 1 || records[0]["msg"] != "dropped push with no commits" || records[0]["repo"] != "cdzombak/dotfiles" {
+		t.Errorf("consolidateActivities() logged %s, want one dropped push record for cdzombak/dotfiles", buf.String())
+	}
+}
+
+func TestLoggingQuietByDefault(t *testing.T) {
+	buf := captureLogs(t, slog.LevelWarn)
+
+	feed := &gofeed.Feed{
+		Link:  "https://github.com/cdzombak",
+		Items: []*gofeed.Item{{Title: "cdzombak made cdzombak/ghfeed public", Link: "https://github.com/cdzombak/ghfeed"}},
+	}
+	consolidateCommits(feed, Options{ConsolidatePushes: true})
+
+	if buf.Len() != 0 {
+		t.Errorf("consolidateCommits() logged at the default level:\n%s", buf.String())
+	}
+}
@@ -3,10 +3,16 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/mail"
+	"net/url"
 	"os"
 	"regexp"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +21,57 @@ import (
 
 var version = "<dev>"
 
+// defaultHost is the GitHub hostname used when none is configured or detected
+const defaultHost = "github.com"
+
+// Options controls how consolidateCommits transforms a feed
+type Options struct {
+	// Title replaces the feed's title when non-empty
+	Title string
+	// ConsolidatePushes groups pushes into a single item per repository/branch
+	ConsolidatePushes bool
+	// Host is the GitHub (or GitHub Enterprise Server) hostname; detected from the feed when empty
+	Host string
+	// PushWindow, when positive, only consolidates pushes to a branch that are less than this far apart
+	PushWindow time.Duration
+	// SessionWindow, when positive, combines an actor's consolidated pushes to several repositories that are
+	// less than this far apart into one item
+	SessionWindow time.Duration
+	// Provider is the forge the feed comes from: github, gitea, or gitlab; empty means github
+	Provider string
+	// LinkTemplate is the git source's commit web link, with {repo}, {branch}, and {hash} placeholders
+	LinkTemplate string
+	// Noise is how automated activity is handled: keep, drop, or collapse; empty means keep
+	Noise string
+	// NoisePatterns are commit messages marking a push as automated when all its commits match
+	NoisePatterns []*regexp.Regexp
+	// Since and Until, when non-zero, limit output to activity from Since until (but not including) Until
+	Since time.Time
+	Until time.Time
+	// MaxItems, when positive, limits output to this many of the most recent items
+	MaxItems int
+	// Match, when set, keeps only commits whose messages match it
+	Match *regexp.Regexp
+	// ExcludeMatch, when set, drops commits whose messages match it
+	ExcludeMatch *regexp.Regexp
+	// Autolink links issue references like #123 and owner/repo#45 in commit messages and items
+	Autolink bool
+	// Trackers link references to external issue trackers, such as JIRA keys
+	Trackers []Tracker
+	// ConventionalCommits groups push items' commits by Conventional Commits type
+	ConventionalCommits bool
+	// Explain, when set, is filled in with how each input item was transformed
+	Explain *Explanation
+	// Warnings, when set, counts the items that fell back to degraded parsing
+	Warnings *ParseWarnings
+	// Pushes, when set, is filled in with the pushes behind each push item, for webhook notifications
+	Pushes PushIndex
+	// Digest, when set, rolls items up into one item per day or week: daily or weekly
+	Digest string
+	// DigestLocation is the time zone digest days start in; nil means local time
+	DigestLocation *time.Location
+}
+
 // Commit represents a single commit with its metadata
 type Commit struct {
 	Hash    string
@@ -24,11 +81,35 @@ type Commit struct {
 
 // BranchActivity represents all commits to a specific repository/branch
 type BranchActivity struct {
+	// Actor is who pushed the commits; empty means the feed's user
+	Actor string
+	// Owner is the repository's owner; empty means the feed's user
+	Owner       string
 	Repo        string
 	Branch      string
 	Commits     []Commit
 	LatestTime  *time.Time
 	CompareLink string
+	// TotalCommits is the true number of commits pushed, including any GitHub omitted from the feed
+	TotalCommits int
+	// MoreCommitsLink is GitHub's "N more commits" compare link, set when the feed truncated a push
+	MoreCommitsLink string
+	// Sources are the input items the push was extracted from; merged pushes have several
+	Sources []*gofeed.Item
+	// Fallbacks are the degraded parsing paths used to extract the push, if any
+	Fallbacks []string
+}
+
+// Activity is a single upstream event after extraction: a push, which may be consolidated with other
+// pushes to the same repository/branch, or an already-simplified item for anything else
+type Activity struct {
+	Push *BranchActivity
+	Item *gofeed.Item
+	// Sources are the input items Item was made from (a push's are in Push.Sources); empty for sources
+	// without input items, such as the Events API
+	Sources []*gofeed.Item
+	// Fallbacks are the degraded parsing paths used to simplify Item (a push's are in Push.Fallbacks)
+	Fallbacks []string
 }
 
 func main() {
@@ -47,11 +128,49 @@ func main() {
 		os.Exit(0)
 	}
 
+	if os.Args[1] == "daemon" {
+		runDaemon(os.Args[2:])
+		return
+	}
+
 	// Parse command line arguments
 	var feedURL string
+	var source = "atom" // default source
+	var provider = providerGitHub
+	var linkTemplate string
+	var noise = noiseKeep
+	var noisePatterns []*regexp.Regexp
+	var since, until time.Time
+	var maxItems int
+	var match, excludeMatch *regexp.Regexp
+	var autolink = false
+	var trackers []Tracker
+	var conventionalCommits = false
 	var customTitle string
+	var githubHost string
+	var tokenFile string
+	var fetchTimeout = defaultFetchTimeout
+	var fetchRetries = defaultFetchRetries
+	var staleCache string
+	var staleItem = false
 	var format = "atom"          // default format
 	var consolidatePushes = true // default to true for backward compatibility
+	var pushWindow time.Duration
+	var sessionWindow time.Duration
+	var logLevel = slog.LevelWarn
+	var logFormat = "text"
+	var explainFormat string
+	var unparseable = unparseableIgnore
+	var webhookURL string
+	var webhookKind = webhookSlack
+	var webhookStatePath string
+	var digest string
+	var emailFrom *mail.Address
+	var emailTo []*mail.Address
+	var mboxPath string
+	var smtpAddr string
+	var smtpUser string
+	var smtpPasswordFile string
 
 	args := os.Args[1:]
 	for i := 0; i < len(args); i++ {
@@ -65,12 +184,154 @@ func main() {
 			i++ // Skip the next argument since we consumed it
 		} else if arg == "-format" {
 			if i+1 >= len(args) {
-				fmt.Fprintf(os.Stderr, "Error: -format flag requires a format argument (atom, rss, or json)\n")
+				fmt.Fprintf(os.Stderr, "Error: -format flag requires a format argument (atom, rss, json, or email)\n")
 				os.Exit(1)
 			}
 			format = args[i+1]
-			if format != "atom" && format != "rss" && format != "json" {
-				fmt.Fprintf(os.Stderr, "Error: format must be 'atom', 'rss', or 'json'\n")
+			if format != "atom" && format != "rss" && format != "json" && format != "email" {
+				fmt.Fprintf(os.Stderr, "Error: format must be 'atom', 'rss', 'json', or 'email'\n")
+				os.Exit(1)
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-source" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -source flag requires a source argument (atom, events-api, or git)\n")
+				os.Exit(1)
+			}
+			source = args[i+1]
+			if source != "atom" && source != "events-api" && source != "git" {
+				fmt.Fprintf(os.Stderr, "Error: source must be 'atom', 'events-api', or 'git'\n")
+				os.Exit(1)
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-link-template" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -link-template flag requires a URL template argument\n")
+				os.Exit(1)
+			}
+			linkTemplate = args[i+1]
+			if !strings.Contains(linkTemplate, "{hash}") {
+				fmt.Fprintf(os.Stderr, "Error: -link-template must contain {hash}\n")
+				os.Exit(1)
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-provider" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -provider flag requires a provider argument (github, gitea, forgejo, or gitlab)\n")
+				os.Exit(1)
+			}
+			provider = normalizeProvider(args[i+1])
+			if provider == "" {
+				fmt.Fprintf(os.Stderr, "Error: provider must be 'github', 'gitea', 'forgejo', or 'gitlab'\n")
+				os.Exit(1)
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-noise" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -noise flag requires an argument (keep, drop, or collapse)\n")
+				os.Exit(1)
+			}
+			noise = args[i+1]
+			if noise != noiseKeep && noise != noiseDrop && noise != noiseCollapse {
+				fmt.Fprintf(os.Stderr, "Error: -noise must be 'keep', 'drop', or 'collapse'\n")
+				os.Exit(1)
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-noise-pattern" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -noise-pattern flag requires a regular expression argument\n")
+				os.Exit(1)
+			}
+			pattern, err := regexp.Compile(args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid -noise-pattern: %v\n", err)
+				os.Exit(1)
+			}
+			noisePatterns = append(noisePatterns, pattern)
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-since" || arg == "-until" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s flag requires a date, time, or duration argument (e.g. 2025-09-01 or 72h)\n", arg)
+				os.Exit(1)
+			}
+			bound, err := parseTimeBound(args[i+1], time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid %s: %v\n", arg, err)
+				os.Exit(1)
+			}
+			if arg == "-since" {
+				since = bound
+			} else {
+				until = bound
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-max-items" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -max-items flag requires a number argument\n")
+				os.Exit(1)
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				fmt.Fprintf(os.Stderr, "Error: -max-items must be a positive integer\n")
+				os.Exit(1)
+			}
+			maxItems = n
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-match" || arg == "-exclude-match" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: %s flag requires a regular expression argument\n", arg)
+				os.Exit(1)
+			}
+			pattern, err := regexp.Compile(args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid %s: %v\n", arg, err)
+				os.Exit(1)
+			}
+			if arg == "-match" {
+				match = pattern
+			} else {
+				excludeMatch = pattern
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-autolink" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -autolink flag requires a boolean argument (true or false)\n")
+				os.Exit(1)
+			}
+			switch args[i+1] {
+			case "true":
+				autolink = true
+			case "false":
+				autolink = false
+			default:
+				fmt.Fprintf(os.Stderr, "Error: -autolink must be 'true' or 'false'\n")
+				os.Exit(1)
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-tracker" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -tracker flag requires a REGEX=URL argument\n")
+				os.Exit(1)
+			}
+			tracker, err := parseTracker(args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid -tracker: %v\n", err)
+				os.Exit(1)
+			}
+			trackers = append(trackers, tracker)
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-conventional-commits" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -conventional-commits flag requires a boolean argument (true or false)\n")
+				os.Exit(1)
+			}
+			switch args[i+1] {
+			case "true":
+				conventionalCommits = true
+			case "false":
+				conventionalCommits = false
+			default:
+				fmt.Fprintf(os.Stderr, "Error: -conventional-commits must be 'true' or 'false'\n")
 				os.Exit(1)
 			}
 			i++ // Skip the next argument since we consumed it
@@ -89,6 +350,233 @@ func main() {
 				os.Exit(1)
 			}
 			i++ // Skip the next argument since we consumed it
+		} else if arg == "-push-window" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -push-window flag requires a duration argument (e.g. 1h)\n")
+				os.Exit(1)
+			}
+			window, err := time.ParseDuration(args[i+1])
+			if err != nil || window < 0 {
+				fmt.Fprintf(os.Stderr, "Error: -push-window must be a duration like 30m or 2h\n")
+				os.Exit(1)
+			}
+			pushWindow = window
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-session-window" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -session-window flag requires a duration argument (e.g. 2h)\n")
+				os.Exit(1)
+			}
+			window, err := time.ParseDuration(args[i+1])
+			if err != nil || window <= 0 {
+				fmt.Fprintf(os.Stderr, "Error: -session-window must be a duration like 30m or 2h\n")
+				os.Exit(1)
+			}
+			sessionWindow = window
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-github-host" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -github-host flag requires a hostname argument\n")
+				os.Exit(1)
+			}
+			githubHost = normalizeHost(args[i+1])
+			if githubHost == "" {
+				fmt.Fprintf(os.Stderr, "Error: -github-host must be a hostname like github.example.com\n")
+				os.Exit(1)
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-token-file" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -token-file flag requires a path argument\n")
+				os.Exit(1)
+			}
+			tokenFile = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-timeout" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -timeout flag requires a duration argument (e.g. 30s)\n")
+				os.Exit(1)
+			}
+			timeout, err := time.ParseDuration(args[i+1])
+			if err != nil || timeout < 0 {
+				fmt.Fprintf(os.Stderr, "Error: -timeout must be a duration like 30s or 2m\n")
+				os.Exit(1)
+			}
+			fetchTimeout = timeout
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-retries" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -retries flag requires a number argument\n")
+				os.Exit(1)
+			}
+			retries, err := strconv.Atoi(args[i+1])
+			if err != nil || retries < 0 {
+				fmt.Fprintf(os.Stderr, "Error: -retries must be a non-negative integer\n")
+				os.Exit(1)
+			}
+			fetchRetries = retries
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-stale-cache" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -stale-cache flag requires a path argument\n")
+				os.Exit(1)
+			}
+			staleCache = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-stale-item" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -stale-item flag requires a boolean argument (true or false)\n")
+				os.Exit(1)
+			}
+			switch args[i+1] {
+			case "true":
+				staleItem = true
+			case "false":
+				staleItem = false
+			default:
+				fmt.Fprintf(os.Stderr, "Error: -stale-item must be 'true' or 'false'\n")
+				os.Exit(1)
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-digest" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -digest flag requires a period argument (daily or weekly)\n")
+				os.Exit(1)
+			}
+			digest = args[i+1]
+			if digest != digestDaily && digest != digestWeekly {
+				fmt.Fprintf(os.Stderr, "Error: -digest must be 'daily' or 'weekly'\n")
+				os.Exit(1)
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-webhook" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -webhook flag requires an incoming webhook URL argument\n")
+				os.Exit(1)
+			}
+			webhookURL = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-webhook-type" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -webhook-type flag requires a type argument (slack, discord, or mattermost)\n")
+				os.Exit(1)
+			}
+			webhookKind = args[i+1]
+			if webhookKind != webhookSlack && webhookKind != webhookDiscord && webhookKind != webhookMattermost {
+				fmt.Fprintf(os.Stderr, "Error: -webhook-type must be 'slack', 'discord', or 'mattermost'\n")
+				os.Exit(1)
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-webhook-state" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -webhook-state flag requires a path argument\n")
+				os.Exit(1)
+			}
+			webhookStatePath = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-email-from" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -email-from flag requires an email address argument\n")
+				os.Exit(1)
+			}
+			address, err := mail.ParseAddress(args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid -email-from: %v\n", err)
+				os.Exit(1)
+			}
+			emailFrom = address
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-email-to" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -email-to flag requires an email address argument\n")
+				os.Exit(1)
+			}
+			addresses, err := mail.ParseAddressList(args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid -email-to: %v\n", err)
+				os.Exit(1)
+			}
+			emailTo = append(emailTo, addresses...)
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-mbox" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -mbox flag requires a path argument\n")
+				os.Exit(1)
+			}
+			mboxPath = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-smtp" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -smtp flag requires a host:port argument\n")
+				os.Exit(1)
+			}
+			if _, _, err := net.SplitHostPort(args[i+1]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: -smtp must be a host:port like smtp.example.com:587\n")
+				os.Exit(1)
+			}
+			smtpAddr = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-smtp-user" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -smtp-user flag requires a username argument\n")
+				os.Exit(1)
+			}
+			smtpUser = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-smtp-password-file" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -smtp-password-file flag requires a path argument\n")
+				os.Exit(1)
+			}
+			smtpPasswordFile = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-unparseable" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -unparseable flag requires an argument (ignore, warn, or fail)\n")
+				os.Exit(1)
+			}
+			unparseable = args[i+1]
+			if unparseable != unparseableIgnore && unparseable != unparseableWarn && unparseable != unparseableFail {
+				fmt.Fprintf(os.Stderr, "Error: -unparseable must be 'ignore', 'warn', or 'fail'\n")
+				os.Exit(1)
+			}
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-explain" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -explain flag requires a format argument (text or json)\n")
+				os.Exit(1)
+			}
+			if args[i+1] != "text" && args[i+1] != "json" {
+				fmt.Fprintf(os.Stderr, "Error: -explain must be 'text' or 'json'\n")
+				os.Exit(1)
+			}
+			explainFormat = args[i+1]
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-verbose" {
+			logLevel = slog.LevelDebug
+		} else if arg == "-log-level" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -log-level flag requires a level argument (debug, info, warn, or error)\n")
+				os.Exit(1)
+			}
+			level, err := parseLogLevel(args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: -log-level %v\n", err)
+				os.Exit(1)
+			}
+			logLevel = level
+			i++ // Skip the next argument since we consumed it
+		} else if arg == "-log-format" {
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: -log-format flag requires a format argument (text or json)\n")
+				os.Exit(1)
+			}
+			if args[i+1] != "text" && args[i+1] != "json" {
+				fmt.Fprintf(os.Stderr, "Error: -log-format must be 'text' or 'json'\n")
+				os.Exit(1)
+			}
+			logFormat = args[i+1]
+			i++ // Skip the next argument since we consumed it
 		} else if feedURL == "" {
 			feedURL = arg
 		} else {
@@ -102,55 +590,351 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Parse the feed
-	fp := gofeed.NewParser()
-	feed, err := fp.ParseURL(feedURL)
+	logger = newLogger(os.Stderr, logLevel, logFormat)
+
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		fmt.Fprintf(os.Stderr, "Error: -since must be before -until\n")
+		os.Exit(1)
+	}
+
+	if sessionWindow > 0 && !consolidatePushes {
+		fmt.Fprintf(os.Stderr, "Error: -session-window requires -consolidate-pushes true\n")
+		os.Exit(1)
+	}
+
+	if source == "events-api" && provider != providerGitHub {
+		fmt.Fprintf(os.Stderr, "Error: -source events-api is only supported with -provider github\n")
+		os.Exit(1)
+	}
+
+	// Explanations map input feed items to output items, so they need an input feed
+	var explanation *Explanation
+	if explainFormat != "" {
+		if source != "atom" {
+			fmt.Fprintf(os.Stderr, "Error: -explain is only supported with -source atom\n")
+			os.Exit(1)
+		}
+		if staleCache != "" {
+			fmt.Fprintf(os.Stderr, "Error: -explain can't be used with -stale-cache\n")
+			os.Exit(1)
+		}
+		explanation = &Explanation{}
+	}
+
+	var warnings *ParseWarnings
+	if unparseable != unparseableIgnore {
+		warnings = &ParseWarnings{}
+	}
+
+	// Webhooks need to remember what they've sent
+	var pushes PushIndex
+	if webhookURL != "" {
+		if webhookStatePath == "" {
+			fmt.Fprintf(os.Stderr, "Error: -webhook requires -webhook-state\n")
+			os.Exit(1)
+		}
+		pushes = PushIndex{}
+	}
+
+	// Emails need addresses, and are delivered to stdout, an mbox file, or an SMTP server
+	var email *Email
+	if format == "email" {
+		if emailFrom == nil || len(emailTo) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: -format email requires -email-from and -email-to\n")
+			os.Exit(1)
+		}
+		if mboxPath != "" && smtpAddr != "" {
+			fmt.Fprintf(os.Stderr, "Error: -mbox and -smtp can't be used together\n")
+			os.Exit(1)
+		}
+		email = &Email{From: emailFrom, To: emailTo, MboxPath: mboxPath}
+		if smtpAddr != "" {
+			password, err := loadSMTPPassword(smtpPasswordFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading SMTP password: %v\n", err)
+				os.Exit(1)
+			}
+			email.SMTP = &Mailer{Addr: smtpAddr, Username: smtpUser, Password: password, Timeout: fetchTimeout}
+		}
+	} else if emailFrom != nil || len(emailTo) > 0 || mboxPath != "" || smtpAddr != "" {
+		fmt.Fprintf(os.Stderr, "Error: -email-from, -email-to, -mbox, and -smtp require -format email\n")
+		os.Exit(1)
+	}
+
+	// Load the access token for private feeds, if any
+	token, err := loadToken(tokenFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing feed: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error loading token: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Process and consolidate the feed
-	consolidatedFeed := consolidateCommits(feed, customTitle, consolidatePushes)
+	// Fetch, parse, and consolidate the feed
+	fetcher := newFetcher(token)
+	fetcher.Timeout = fetchTimeout
+	fetcher.MaxRetries = fetchRetries
+	consolidatedFeed, err := fetchConsolidatedFeed(fetcher, source, feedURL, Options{
+		Title:               customTitle,
+		ConsolidatePushes:   consolidatePushes,
+		Host:                githubHost,
+		PushWindow:          pushWindow,
+		SessionWindow:       sessionWindow,
+		Provider:            provider,
+		LinkTemplate:        linkTemplate,
+		Noise:               noise,
+		NoisePatterns:       noisePatterns,
+		Since:               since,
+		Until:               until,
+		MaxItems:            maxItems,
+		Match:               match,
+		ExcludeMatch:        excludeMatch,
+		Autolink:            autolink,
+		Trackers:            trackers,
+		ConventionalCommits: conventionalCommits,
+		Explain:             explanation,
+		Warnings:            warnings,
+		Pushes:              pushes,
+		Digest:              digest,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing feed: %v\n", err)
+		if staleCache == "" {
+			os.Exit(1)
+		}
+
+		// Re-emit the last good feed rather than failing outright
+		cachedFeed, savedAt, err := loadStaleCache(staleCache)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading stale feed: %v\n", err)
+			os.Exit(1)
+		}
+		if staleItem {
+			cachedFeed.Items = append([]*gofeed.Item{createStaleItem(cachedFeed, savedAt, time.Now())}, cachedFeed.Items...)
+		}
+		fmt.Fprintf(os.Stderr, "Serving stale feed last updated %s\n", savedAt.Format(time.RFC3339))
+
+		if email != nil {
+			err = email.deliver(os.Stdout, cachedFeed, time.Now())
+		} else {
+			err = renderFeed(os.Stdout, cachedFeed, format)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering feed: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(exitStale)
+	}
+
+	// Report items that weren't fully understood, so parser bugs get noticed
+	warnings.log()
+	if unparseable == unparseableFail && warnings.Total() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: %d items fell back to degraded parsing\n", warnings.Items)
+		os.Exit(exitUnparseable)
+	}
+
+	// Report how the feed was transformed instead of rendering it
+	if explanation != nil {
+		if err := renderExplanation(os.Stdout, explanation, explainFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering explanation: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Render in the specified format
-	err = renderFeed(consolidatedFeed, format)
+	if email != nil {
+		err = email.deliver(os.Stdout, consolidatedFeed, time.Now())
+	} else {
+		err = renderFeed(os.Stdout, consolidatedFeed, format)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error rendering feed: %v\n", err)
 		os.Exit(1)
 	}
+
+	// Keep this feed to serve if a later fetch fails
+	if staleCache != "" {
+		err = saveStaleCache(staleCache, consolidatedFeed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving stale feed: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Post new items to the webhook
+	if webhookURL != "" {
+		webhook := &Webhook{URL: webhookURL, Kind: webhookKind, StatePath: webhookStatePath}
+		if err := webhook.Notify(consolidatedFeed, pushes, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error notifying webhook: %v\n", err)
+			os.Exit(1)
+		}
+	}
 }
 
-// renderFeed outputs the feed in the specified format
-func renderFeed(feed *gofeed.Feed, format string) error {
+// fetchConsolidatedFeed fetches target from the given source and consolidates it
+func fetchConsolidatedFeed(fetcher *Fetcher, source, target string, opts Options) (*gofeed.Feed, error) {
+	switch source {
+	case "atom":
+		feed, err := fetcher.fetchFeed(target)
+		if err != nil {
+			return nil, err
+		}
+		return consolidateCommits(feed, opts), nil
+	case "events-api":
+		return fetchEventsFeed(fetcher, target, opts)
+	case "git":
+		return readGitFeed(target, opts)
+	default:
+		return nil, fmt.Errorf("unsupported source: %s", source)
+	}
+}
+
+// renderFeed writes the feed to w in the specified format
+func renderFeed(w io.Writer, feed *gofeed.Feed, format string) error {
+	defer metrics.RenderDuration.ObserveDuration(time.Now(), format)
+
 	switch format {
 	case "atom":
-		return feed.RenderAtom(os.Stdout, nil)
+		return feed.RenderAtom(w, nil)
 	case "rss":
-		return feed.RenderRSS(os.Stdout, nil)
+		return feed.RenderRSS(w, nil)
 	case "json":
-		return renderJSON(feed)
+		return renderJSON(w, feed)
 	default:
 		return fmt.Errorf("unsupported format: %s", format)
 	}
 }
 
-// renderJSON outputs the feed as JSON
-func renderJSON(feed *gofeed.Feed) error {
-	encoder := json.NewEncoder(os.Stdout)
+// renderJSON writes the feed to w as JSON
+func renderJSON(w io.Writer, feed *gofeed.Feed) error {
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(feed)
 }
 
 // consolidateCommits groups commit/push activities by repository/branch and returns a new feed
-func consolidateCommits(feed *gofeed.Feed, customTitle string, consolidatePushes bool) *gofeed.Feed {
+func consolidateCommits(feed *gofeed.Feed, opts Options) *gofeed.Feed {
+	// Use the configured GitHub host, or detect it from the feed
+	host := opts.Host
+	if host == "" {
+		host = detectHost(feed)
+	}
+
+	// Other forges' activity feeds have their own markup
+	switch opts.Provider {
+	case providerGitea:
+		username := extractUsername(feed, host)
+		return consolidateActivities(feed, extractGiteaActivities(feed, username, host), username, host, opts)
+	case providerGitLab:
+		username := extractUsername(feed, host)
+		return consolidateActivities(feed, extractGitLabActivities(feed, username, host), username, host, opts)
+	}
+
+	// Per-repository commits and releases feeds have their own entry formats
+	if repoFeed := detectRepoFeed(feed, host); repoFeed != nil {
+		switch repoFeed.Kind {
+		case FeedKindCommits:
+			if opts.PushWindow <= 0 {
+				opts.PushWindow = defaultCommitWindow
+			}
+			return consolidateActivities(feed, extractCommitFeedActivities(feed, repoFeed, host), repoFeed.Owner, host, opts)
+		case FeedKindReleases:
+			return consolidateActivities(feed, extractReleaseFeedActivities(feed, repoFeed), repoFeed.Owner, host, opts)
+		}
+	}
+
 	// Extract username from feed link or items
-	username := extractUsername(feed)
+	username := extractUsername(feed, host)
+
+	return consolidateActivities(feed, extractActivities(feed, username, host), username, host, opts)
+}
+
+// extractActivities classifies each item in an Atom feed, extracting pushes and simplifying everything else
+func extractActivities(feed *gofeed.Feed, username, host string) []Activity {
+	activities := []Activity{}
+	for _, item := range feed.Items {
+		// Team and organization feeds mix several people's activity
+		actor := itemActor(item, username)
+		if isCommitOrPush(item.Title) {
+			if push := extractActorBranchActivity(item, actor, username, host); push != nil {
+				push.Sources = []*gofeed.Item{item}
+				logger.Debug("classified item as push", "title", item.Title, "repo", push.Repo, "branch", push.Branch, "commits", commitCount(push))
+				activities = append(activities, Activity{Push: push})
+				continue
+			}
+			// If we can't extract branch activity, simplify it like any other item
+			logger.Debug("push item has no repository link; simplifying it as other activity", "title", item.Title, "link", item.Link)
+			activities = append(activities, Activity{Item: simplifyNonCommitItem(item, actor, host), Sources: []*gofeed.Item{item}, Fallbacks: []string{fallbackNoRepo}})
+			continue
+		}
+		var fallbacks []string
+		if detectActivityType(item) == ActivityOther {
+			fallbacks = append(fallbacks, fallbackUnrecognized)
+		}
+		activities = append(activities, Activity{Item: simplifyNonCommitItem(item, actor, host), Sources: []*gofeed.Item{item}, Fallbacks: fallbacks})
+	}
+	return activities
+}
+
+// itemActor returns who performed an item's activity: the item's author, or the username its title starts
+// with, falling back to the feed's user. Logins are case-insensitive, so the feed's user in any case is
+// returned as username.
+func itemActor(item *gofeed.Item, username string) string {
+	loginRegex := regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?(?:\[bot\])?$`)
+	actor := username
+	titleRegex := regexp.MustCompile(`^(\S+) (?:pushed|opened|closed|reopened|merged|created|deleted|forked|starred|commented|released|published|made|contributed|added|started|joined)\b`)
+	if len(item.Authors) > 0 && item.Authors[0] != nil && loginRegex.MatchString(item.Authors[0].Name) {
+		actor = item.Authors[0].Name
+	} else if matches := titleRegex.FindStringSubmatch(item.Title); matches != nil && loginRegex.MatchString(matches[1]) {
+		actor = matches[1]
+	}
+
+	if strings.EqualFold(actor, username) {
+		return username
+	}
+	return actor
+}
+
+// extractActorBranchActivity extracts a push by actor, which may be to any owner's repository. Pushes by
+// other actors record who pushed them.
+func extractActorBranchActivity(item *gofeed.Item, actor, username, host string) *BranchActivity {
+	push := extractBranchActivity(item, username, host)
+	if push != nil && actor != username {
+		push.Actor = actor
+	}
+	return push
+}
+
+// pushKey returns the key pushes are consolidated by: actor, repository, and branch
+func pushKey(push *BranchActivity) string {
+	return fmt.Sprintf("%s@%s/%s/%s", push.Actor, push.Owner, push.Repo, push.Branch)
+}
+
+// consolidateActivities builds the output feed from extracted activities, copying metadata from feed.
+// It's shared by every input source, so output looks the same regardless of where activities came from.
+func consolidateActivities(feed *gofeed.Feed, activities []Activity, username, host string, opts Options) *gofeed.Feed {
+	explain := opts.Explain
+	explain.describe(feed, activities, username, host, opts.Provider)
+
+	// Digests need the push behind each item too
+	pushIndex := opts.Pushes
+	if pushIndex == nil && opts.Digest != "" {
+		pushIndex = PushIndex{}
+	}
+	opts.Warnings.record(activities)
+
+	// Drop or collapse automated activity, activity outside the date range, and unwanted commits before
+	// building items
+	activities = explain.filtered(activities, filterNoise(activities, username, host, opts), "automated activity")
+	activities = explain.filtered(activities, filterDateRange(activities, opts.Since, opts.Until), "outside -since/-until")
+	activities = explain.filtered(activities, filterCommits(activities, opts.Match, opts.ExcludeMatch, username, host, opts.Provider), "no commits matching -match/-exclude-match")
+
+	// Link issue references in what's left
+	autolinkActivities(activities, username, host, opts)
 
 	// Create new feed with same metadata
 	title := feed.Title
-	if customTitle != "" {
-		title = customTitle
+	if opts.Title != "" {
+		title = opts.Title
 	}
 	newFeed := &gofeed.Feed{
 		Title:         title,
@@ -170,69 +954,76 @@ func consolidateCommits(feed *gofeed.Feed, customTitle string, consolidatePushes
 		Items:         []*gofeed.Item{},
 	}
 
-	// Group items by repository/branch for commits/pushes (if consolidating)
-	if consolidatePushes {
-		branchGroups := make(map[string]*BranchActivity)
-		nonCommitItems := []*gofeed.Item{}
-
-		for _, item := range feed.Items {
-			if isCommitOrPush(item.Title) {
-				activity := extractBranchActivity(item, username)
-				if activity != nil {
-					key := fmt.Sprintf("%s/%s", activity.Repo, activity.Branch)
-					if existing, exists := branchGroups[key]; exists {
-						// Merge commits and update latest time
-						existing.Commits = append(existing.Commits, activity.Commits...)
-						if activity.LatestTime != nil && (existing.LatestTime == nil || activity.LatestTime.After(*existing.LatestTime)) {
-							existing.LatestTime = activity.LatestTime
-							existing.CompareLink = activity.CompareLink
-						}
-					} else {
-						branchGroups[key] = activity
-					}
-				} else {
-					// If we can't extract branch activity, keep as-is
-					nonCommitItems = append(nonCommitItems, item)
-				}
-			} else {
-				nonCommitItems = append(nonCommitItems, item)
+	// Group pushes by actor and repository/branch (if consolidating)
+	if opts.ConsolidatePushes {
+		branchGroups := make(map[string][]*BranchActivity)
+
+		for _, activity := range activities {
+			if activity.Push == nil {
+				newFeed.Items = append(newFeed.Items, activity.Item)
+				explain.output(activity.Sources, activity.Item.GUID, "")
+				continue
 			}
+
+			push := activity.Push
+			key := pushKey(push)
+			branchGroups[key] = append(branchGroups[key], push)
 		}
 
-		// Create consolidated items for each repository/branch
-		for _, activity := range branchGroups {
-			// Generate proper comparison link that encompasses all commits
-			activity.CompareLink = generateComparisonLink(activity, username)
-			consolidatedItem := createConsolidatedBranchItem(activity, username)
-			if consolidatedItem != nil {
-				newFeed.Items = append(newFeed.Items, consolidatedItem)
+		// Merge the pushes to each repository/branch
+		var merged []*BranchActivity
+		keys := make(map[*BranchActivity]string)
+		for key, pushes := range branchGroups {
+			for _, push := range mergePushes(pushes, opts.PushWindow) {
+				if len(push.Commits) == 0 {
+					logger.Debug("dropped push with no commits", activityAttrs(Activity{Push: push}, username)...)
+					for _, source := range push.Sources {
+						explain.drop(source, "push with no commits")
+					}
+					continue
+				}
+				// Generate proper comparison link that encompasses all commits
+				push.CompareLink = generateComparisonLink(push, username, host, opts.Provider)
+				merged = append(merged, push)
+				keys[push] = key
 			}
 		}
 
-		// Process and simplify non-commit items
-		for _, item := range nonCommitItems {
-			simplifiedItem := simplifyNonCommitItem(item, username)
-			newFeed.Items = append(newFeed.Items, simplifiedItem)
+		// Create consolidated items for each repository/branch, or for each session across repositories
+		for _, session := range groupSessions(merged, opts.SessionWindow, username) {
+			var consolidatedItem *gofeed.Item
+			if len(session) == 1 {
+				consolidatedItem = createConsolidatedBranchItem(session[0], username, opts.ConventionalCommits)
+			} else {
+				consolidatedItem = createSessionItem(session, username, opts.ConventionalCommits)
+			}
+			newFeed.Items = append(newFeed.Items, consolidatedItem)
+			for _, push := range session {
+				explain.output(push.Sources, consolidatedItem.GUID, keys[push])
+				metrics.CommitsConsolidated.Add(float64(commitCount(push)))
+			}
+			pushIndex.add(consolidatedItem.GUID, session...)
 		}
 	} else {
-		// Process each item individually without consolidation
-		for _, item := range feed.Items {
-			if isCommitOrPush(item.Title) {
-				activity := extractBranchActivity(item, username)
-				if activity != nil {
-					individualItem := createIndividualPushItem(activity, username)
-					if individualItem != nil {
-						newFeed.Items = append(newFeed.Items, individualItem)
-					}
-				} else {
-					// If we can't extract branch activity, keep as-is
-					simplifiedItem := simplifyNonCommitItem(item, username)
-					newFeed.Items = append(newFeed.Items, simplifiedItem)
+		// Process each activity individually without consolidation
+		for _, activity := range activities {
+			if activity.Push == nil {
+				newFeed.Items = append(newFeed.Items, activity.Item)
+				explain.output(activity.Sources, activity.Item.GUID, "")
+				continue
+			}
+
+			individualItem := createIndividualPushItem(activity.Push, username, opts.ConventionalCommits)
+			if individualItem == nil {
+				logger.Debug("dropped push with no commits", activityAttrs(activity, username)...)
+				for _, source := range activity.Push.Sources {
+					explain.drop(source, "push with no commits")
 				}
-			} else {
-				simplifiedItem := simplifyNonCommitItem(item, username)
-				newFeed.Items = append(newFeed.Items, simplifiedItem)
+				continue
 			}
+			newFeed.Items = append(newFeed.Items, individualItem)
+			explain.output(activity.Push.Sources, individualItem.GUID, "")
+			pushIndex.add(individualItem.GUID, activity.Push)
 		}
 	}
 
@@ -259,9 +1050,124 @@ func consolidateCommits(feed *gofeed.Feed, customTitle string, consolidatePushes
 		return dateI.After(*dateJ)
 	})
 
+	// Roll items up into one per day or week
+	if opts.Digest != "" {
+		var digested map[string]string
+		newFeed.Items, digested = digestItems(newFeed.Items, pushIndex, username, opts.Digest, opts.DigestLocation)
+		explain.digested(digested)
+	}
+
+	// Keep only the most recent items
+	if opts.MaxItems > 0 && len(newFeed.Items) > opts.MaxItems {
+		logger.Debug("dropped items beyond -max-items", "items", len(newFeed.Items)-opts.MaxItems)
+		explain.truncated(newFeed.Items[opts.MaxItems:])
+		newFeed.Items = newFeed.Items[:opts.MaxItems]
+	}
+
 	return newFeed
 }
 
+// mergePushes merges pushes to the same repository/branch. With a positive window, pushes are merged
+// only while each is within window of the next newer one, so the result may contain several groups.
+func mergePushes(pushes []*BranchActivity, window time.Duration) []*BranchActivity {
+	if window > 0 {
+		// Work newest-first so merged commit lists stay in newest-first order
+		sort.SliceStable(pushes, func(i, j int) bool {
+			return pushTime(pushes[i]).After(pushTime(pushes[j]))
+		})
+	}
+
+	var groups []*BranchActivity
+	// members holds copies of each group's pushes as they were before merging
+	var members [][]BranchActivity
+	var current, previous *BranchActivity
+	for _, push := range pushes {
+		if current != nil && (window <= 0 || pushTime(previous).Sub(pushTime(push)) <= window) {
+			members[len(members)-1] = append(members[len(members)-1], *push)
+			// Merge commits and update latest time
+			current.TotalCommits = commitCount(current) + commitCount(push)
+			current.Commits = append(current.Commits, push.Commits...)
+			if push.MoreCommitsLink != "" && current.MoreCommitsLink == "" {
+				current.MoreCommitsLink = push.MoreCommitsLink
+			}
+			current.Sources = append(current.Sources, push.Sources...)
+			if push.LatestTime != nil && (current.LatestTime == nil || push.LatestTime.After(*current.LatestTime)) {
+				current.LatestTime = push.LatestTime
+				current.CompareLink = push.CompareLink
+			}
+		} else {
+			members = append(members, []BranchActivity{*push})
+			current = push
+			groups = append(groups, current)
+		}
+		previous = push
+	}
+
+	// One truncated push's compare view doesn't cover the others merged with it
+	for i, group := range groups {
+		if len(members[i]) > 1 && commitCount(group) > len(group.Commits) {
+			if link := spanningCompareLink(members[i]); link != "" {
+				group.MoreCommitsLink = link
+			}
+		}
+	}
+
+	return groups
+}
+
+// compareLinkRegex splits a compare URL (e.g., https://github.com/owner/repo/compare/a...b) into its
+// prefix, base, and head
+var compareLinkRegex = regexp.MustCompile(`^(.+/compare/)([^/]+?)\.\.\.([^/]+)$`)
+
+// spanningCompareLink returns a compare link from the base of the oldest push to the head of the newest,
+// or an empty string if their links and commits don't give both ends
+func spanningCompareLink(pushes []BranchActivity) string {
+	sort.SliceStable(pushes, func(i, j int) bool {
+		return pushTime(&pushes[i]).Before(pushTime(&pushes[j]))
+	})
+	oldest, newest := &pushes[0], &pushes[len(pushes)-1]
+
+	var prefix, base, head string
+	if m := compareLinkRegex.FindStringSubmatch(pushRangeLink(oldest)); m != nil {
+		prefix, base = m[1], m[2]
+	} else if len(oldest.Commits) > 0 && commitCount(oldest) == len(oldest.Commits) {
+		// Every commit is listed, so the oldest one's parent is the base
+		if hash := extractCommitHashFromLink(oldest.Commits[len(oldest.Commits)-1].Link); hash != "" {
+			base = hash + "^"
+		}
+	}
+	if m := compareLinkRegex.FindStringSubmatch(pushRangeLink(newest)); m != nil {
+		if prefix != "" && prefix != m[1] {
+			return ""
+		}
+		prefix, head = m[1], m[3]
+	} else if len(newest.Commits) > 0 {
+		head = extractCommitHashFromLink(newest.Commits[0].Link)
+	}
+
+	if prefix == "" || base == "" || head == "" {
+		return ""
+	}
+	return prefix + base + "..." + head
+}
+
+// pushRangeLink returns the link to the push's own range of commits: GitHub's "N more commits" link if
+// it was truncated, or else its compare link
+func pushRangeLink(push *BranchActivity) string {
+	if push.MoreCommitsLink != "" {
+		return push.MoreCommitsLink
+	}
+	return push.CompareLink
+}
+
+// pushTime returns the push's latest time, or the zero time if it's unknown
+func pushTime(push *BranchActivity) time.Time {
+	if push.LatestTime == nil {
+		return time.Time{}
+	}
+	return *push.LatestTime
+}
+
 // isCommitOrPush determines if an item represents a commit or push activity
 func isCommitOrPush(title string) bool {
 	commitPushPatterns := []string{
@@ -281,11 +1187,47 @@ func isCommitOrPush(title string) bool {
 	return false
 }
 
+// normalizeHost reduces a hostname or base URL (e.g., https://github.example.com/) to a bare hostname
+func normalizeHost(host string) string {
+	host = strings.TrimSpace(host)
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	host = strings.TrimSuffix(host, "/")
+	if strings.Contains(host, "/") {
+		return ""
+	}
+	return strings.ToLower(host)
+}
+
+// detectHost determines the GitHub hostname from the feed's links, defaulting to github.com
+func detectHost(feed *gofeed.Feed) string {
+	for _, link := range []string{feed.Link, feed.FeedLink} {
+		if link == "" {
+			continue
+		}
+		parsed, err := url.Parse(link)
+		if err == nil && parsed.Host != "" {
+			return strings.ToLower(parsed.Host)
+		}
+	}
+	return defaultHost
+}
+
+// hostURL returns the base web URL for a GitHub hostname
+func hostURL(host string) string {
+	return "https://" + host
+}
+
 // extractUsername extracts the GitHub username from the feed
-func extractUsername(feed *gofeed.Feed) string {
+func extractUsername(feed *gofeed.Feed, host string) string {
+	// Organization feeds are about the organization, not whoever is reading them
+	if org := detectOrgFeed(feed, host); org != "" {
+		return org
+	}
+
 	// Try to extract from feed link first (e.g., https://github.com/username.atom)
 	if feed.Link != "" {
-		userRegex := regexp.MustCompile(`github\.com/([^/\.]+)(?:\.atom)?`)
+		userRegex := regexp.MustCompile(regexp.QuoteMeta(host) + `/([^/\.]+)(?:\.atom)?`)
 		matches := userRegex.FindStringSubmatch(feed.Link)
 		if len(matches) > 1 {
 			return matches[1]
@@ -295,7 +1237,7 @@ func extractUsername(feed *gofeed.Feed) string {
 	// Try to extract from feed items
 	for _, item := range feed.Items {
 		if item.Link != "" {
-			userRegex := regexp.MustCompile(`github\.com/([^/]+)/`)
+			userRegex := regexp.MustCompile(regexp.QuoteMeta(host) + `/([^/]+)/`)
 			matches := userRegex.FindStringSubmatch(item.Link)
 			if len(matches) > 1 {
 				return matches[1]
@@ -307,48 +1249,84 @@ func extractUsername(feed *gofeed.Feed) string {
 	return "user"
 }
 
-// extractBranchActivity extracts repository, branch, and commit data from a push item
-func extractBranchActivity(item *gofeed.Item, username string) *BranchActivity {
-	// Extract repo name from link
-	repoName := ""
+// extractBranchActivity extracts repository, branch, and commit data from a push item. Pushes to repositories
+// not owned by username record their owner.
+func extractBranchActivity(item *gofeed.Item, username, host string) *BranchActivity {
+	// Extract owner and repo name from link
+	owner, repoName := "", ""
 	if item.Link != "" {
-		repoLinkRegex := regexp.MustCompile(`github\.com/` + regexp.QuoteMeta(username) + `/([\w-]+)`)
+		repoLinkRegex := regexp.MustCompile(regexp.QuoteMeta(host) + `/([\w-]+)/([\w-]+)`)
 		matches := repoLinkRegex.FindStringSubmatch(item.Link)
-		if len(matches) > 1 {
-			repoName = matches[1]
+		if len(matches) > 2 {
+			owner, repoName = matches[1], matches[2]
 		}
 	}
+	if strings.EqualFold(owner, username) {
+		owner = ""
+	}
 
 	if repoName == "" {
 		return nil
 	}
 
+	// Branch and tag creations and deletions have no commits, and may have no branch link
+	refOnly := !strings.Contains(strings.ToLower(item.Title), "pushed")
+
 	// Extract branch name from content
 	branchName := "master" // default
+	branchFound := false
 	if item.Content != "" {
 		branchRegex := regexp.MustCompile(`<a class="branch-name"[^>]*href="[^"]*/tree/([^"]*)"[^>]*>([^<]+)</a>`)
 		matches := branchRegex.FindStringSubmatch(item.Content)
 		if len(matches) > 2 {
 			branchName = matches[2]
+			branchFound = true
 		}
 	}
+	var fallbacks []string
+	if !branchFound && !refOnly {
+		logger.Debug("no branch link in push; assuming master", "title", item.Title)
+		fallbacks = append(fallbacks, fallbackNoBranch)
+	}
 
 	// Extract commits from content
-	commits := extractCommitsFromContent(item.Content)
+	commits, method := parseCommitsFromContent(item.Content, host)
+	if len(commits) == 0 && !refOnly {
+		fallbacks = append(fallbacks, fallbackNoCommits)
+	} else if method == commitsFromLinks && len(commits) > 0 {
+		fallbacks = append(fallbacks, fallbackCommitLinks)
+	}
+
+	// GitHub lists only a few commits for large pushes, followed by an "N more commits" link
+	moreCount, moreLink := extractMoreCommits(item.Content, host)
 
 	activity := &BranchActivity{
-		Repo:        repoName,
-		Branch:      branchName,
-		Commits:     commits,
-		LatestTime:  item.PublishedParsed,
-		CompareLink: item.Link,
+		Owner:           owner,
+		Repo:            repoName,
+		Branch:          branchName,
+		Commits:         commits,
+		LatestTime:      item.PublishedParsed,
+		CompareLink:     item.Link,
+		TotalCommits:    len(commits) + moreCount,
+		MoreCommitsLink: moreLink,
+		Fallbacks:       fallbacks,
 	}
 
 	return activity
 }
 
+// commitsFromLinks is parseCommitsFromContent's last resort, which finds commit links but not messages
+const commitsFromLinks = "commit links (no messages)"
+
 // extractCommitsFromContent parses commit information from HTML content
-func extractCommitsFromContent(content string) []Commit {
+func extractCommitsFromContent(content, host string) []Commit {
+	commits, _ := parseCommitsFromContent(content, host)
+	return commits
+}
+
+// parseCommitsFromContent parses commit information from HTML content, also returning which markup it was
+// found in
+func parseCommitsFromContent(content, host string) ([]Commit, string) {
 	var commits []Commit
 
 	// More flexible regex to match commit entries in the HTML
@@ -361,14 +1339,17 @@ func extractCommitsFromContent(content string) []Commit {
 			commit := Commit{
 				Hash:    match[3], // short hash
 				Message: strings.TrimSpace(match[4]),
-				Link:    "https://github.com" + match[1], // full commit URL
+				Link:    hostURL(host) + match[1], // full commit URL
 			}
 			commits = append(commits, commit)
 		}
 	}
 
+	method := "blockquote"
+
 	// If no commits found with the above regex, try without the div wrapper
 	if len(commits) == 0 {
+		method = "bare blockquote"
 		simpleBlockquoteRegex := regexp.MustCompile(`<code[^>]*><a[^>]*href="([^"]*commit/([a-f0-9]+))"[^>]*>([a-f0-9]+)</a></code>.*?<blockquote[^>]*>\s*([^<]*?)\s*</blockquote>`)
 		matches = simpleBlockquoteRegex.FindAllStringSubmatch(content, -1)
 
@@ -377,7 +1358,7 @@ func extractCommitsFromContent(content string) []Commit {
 				commit := Commit{
 					Hash:    match[3], // short hash
 					Message: strings.TrimSpace(match[4]),
-					Link:    "https://github.com" + match[1], // full commit URL
+					Link:    hostURL(host) + match[1], // full commit URL
 				}
 				commits = append(commits, commit)
 			}
@@ -386,33 +1367,75 @@ func extractCommitsFromContent(content string) []Commit {
 
 	// If still no commits found, try to extract just from links to commits
 	if len(commits) == 0 {
+		method = commitsFromLinks
 		linkRegex := regexp.MustCompile(`href="([^"]*commit/([a-f0-9]+))"[^>]*>([a-f0-9]+)</a>`)
 		linkMatches := linkRegex.FindAllStringSubmatch(content, -1)
 
 		for _, match := range linkMatches {
 			if len(match) >= 4 {
 				commit := Commit{
-					Hash:    match[3],                        // short hash
-					Message: "Commit " + match[3],            // fallback message
-					Link:    "https://github.com" + match[1], // full commit URL
+					Hash:    match[3],                 // short hash
+					Message: "Commit " + match[3],     // fallback message
+					Link:    hostURL(host) + match[1], // full commit URL
 				}
 				commits = append(commits, commit)
 			}
 		}
 	}
 
+	if len(commits) == 0 {
+		logger.Debug("found no commits in push content")
+	} else {
+		logger.Debug("extracted commits", "method", method, "commits", len(commits))
+	}
+
 	// Reverse the order to make commits newest-first (GitHub Atom feeds have commits oldest-to-newest)
 	slices.Reverse(commits)
 
-	return commits
+	return commits, method
+}
+
+// extractMoreCommits parses GitHub's "N more commits »" truncation marker, returning the number of
+// omitted commits and the compare link for the full push
+func extractMoreCommits(content, host string) (int, string) {
+	moreRegex := regexp.MustCompile(`<a[^>]*href="([^"]*)"[^>]*>\s*([\d,]+) more commits?\s*(?:»|&raquo;)?\s*</a>`)
+	matches := moreRegex.FindStringSubmatch(content)
+	if len(matches) < 3 {
+		return 0, ""
+	}
+
+	count, err := strconv.Atoi(strings.ReplaceAll(matches[2], ",", ""))
+	if err != nil {
+		return 0, ""
+	}
+
+	link := matches[1]
+	if strings.HasPrefix(link, "/") {
+		link = hostURL(host) + link
+	}
+
+	return count, link
 }
 
-// generateComparisonLink creates a GitHub comparison link that encompasses all commits in the activity
-func generateComparisonLink(activity *BranchActivity, username string) string {
+// commitCount returns the true number of commits in the activity, falling back to the listed commits
+func commitCount(activity *BranchActivity) int {
+	if activity.TotalCommits > len(activity.Commits) {
+		return activity.TotalCommits
+	}
+	return len(activity.Commits)
+}
+
+// generateComparisonLink creates a comparison link on the provider that encompasses all commits in the activity
+func generateComparisonLink(activity *BranchActivity, username, host, provider string) string {
 	if len(activity.Commits) == 0 {
 		return activity.CompareLink // fallback to original
 	}
 
+	// If GitHub truncated the push, the listed commits don't span it; use GitHub's own compare view
+	if activity.MoreCommitsLink != "" && commitCount(activity) > len(activity.Commits) {
+		return activity.MoreCommitsLink
+	}
+
 	// If only one commit, link directly to it
 	if len(activity.Commits) == 1 {
 		return activity.Commits[0].Link
@@ -428,7 +1451,13 @@ func generateComparisonLink(activity *BranchActivity, username string) string {
 	newestHash := extractCommitHashFromLink(newestCommit.Link)
 
 	if oldestHash != "" && newestHash != "" && oldestHash != newestHash {
-		return fmt.Sprintf("https://github.com/%s/%s/compare/%s^...%s", username, activity.Repo, oldestHash, newestHash)
+		owner := activity.Owner
+		if owner == "" {
+			owner = username
+		}
+		if link := compareURL(provider, host, owner, activity.Repo, oldestHash+"^", newestHash); link != "" {
+			return link
+		}
 	}
 
 	// Fallback to newest commit (first in array) if we can't create comparison
@@ -445,38 +1474,34 @@ func extractCommitHashFromLink(link string) string {
 	return ""
 }
 
-// createConsolidatedBranchItem creates a single item representing all commits to a repository/branch
-func createConsolidatedBranchItem(activity *BranchActivity, username string) *gofeed.Item {
-	if len(activity.Commits) == 0 {
-		return nil
-	}
-
-	// Count commits for title
-	commitCount := len(activity.Commits)
-	commitWord := "commits"
-	if commitCount == 1 {
-		commitWord = "commit"
-	}
-	title := fmt.Sprintf("%s pushed %d %s to %s/%s", username, commitCount, commitWord, activity.Repo, activity.Branch)
-
-	// Create HTML description with commit details
-	var htmlParts []string
-	htmlParts = append(htmlParts, "<div>")
+// pushDetailsHTML renders a push's commit list, a note of any commits GitHub left out of the feed, and
+// its compare link
+func pushDetailsHTML(activity *BranchActivity, conventional bool) []string {
+	htmlParts := commitListHTML(activity.Commits, conventional)
 
-	for _, commit := range activity.Commits {
-		commitHTML := fmt.Sprintf(
+	// Note commits GitHub left out of the feed
+	if hidden := commitCount(activity) - len(activity.Commits); hidden > 0 {
+		moreLink := activity.MoreCommitsLink
+		if moreLink == "" {
+			moreLink = activity.CompareLink
+		}
+		hiddenWord := "commits"
+		if hidden == 1 {
+			hiddenWord = "commit"
+		}
+		htmlParts = append(htmlParts, fmt.Sprintf(
 			"<div style='margin-bottom: 12px;'>"+
-				"<tt><a href='%s'>%s</a></tt>: %s"+
+				"<a href='%s'>%d more %s</a>"+
 				"</div>",
-			commit.Link,
-			commit.Hash,
-			commit.Message,
-		)
-		htmlParts = append(htmlParts, commitHTML)
+			moreLink,
+			hidden,
+			hiddenWord,
+		))
 	}
 
-	// Add compare link if available
-	if activity.CompareLink != "" {
+	// Add compare link if available. Without a compare view (e.g., the git source), a push of several
+	// commits links to just its newest commit, which isn't all of its changes.
+	if activity.CompareLink != "" && (len(activity.Commits) < 2 || activity.CompareLink != activity.Commits[0].Link) {
 		htmlParts = append(htmlParts, fmt.Sprintf(
 			"<div style='margin-top: 16px; border-top: 1px solid #eee; padding-top: 8px;'>"+
 				"<a href='%s'>View all changes</a>"+
@@ -485,6 +1510,38 @@ func createConsolidatedBranchItem(activity *BranchActivity, username string) *go
 		))
 	}
 
+	return htmlParts
+}
+
+// createConsolidatedBranchItem creates a single item representing all commits to a repository/branch.
+// With conventional set, commits are grouped by Conventional Commits type.
+func createConsolidatedBranchItem(activity *BranchActivity, username string, conventional bool) *gofeed.Item {
+	if len(activity.Commits) == 0 {
+		return nil
+	}
+
+	// Count commits for title, including any GitHub omitted from the feed
+	count := commitCount(activity)
+	commitWord := "commits"
+	if count == 1 {
+		commitWord = "commit"
+	}
+	actor := username
+	if activity.Actor != "" {
+		actor = activity.Actor
+	}
+	title := fmt.Sprintf("%s pushed %d %s to %s/%s", actor, count, commitWord, pushRepoName(activity, username), activity.Branch)
+	if conventional {
+		if summary := conventionalSummary(activity.Commits); summary != "" {
+			title += fmt.Sprintf(" (%s)", summary)
+		}
+	}
+
+	// Create HTML description with commit details
+	var htmlParts []string
+	htmlParts = append(htmlParts, "<div>")
+	htmlParts = append(htmlParts, pushDetailsHTML(activity, conventional)...)
+
 	htmlParts = append(htmlParts, "</div>")
 	htmlContent := strings.Join(htmlParts, "")
 
@@ -498,51 +1555,62 @@ func createConsolidatedBranchItem(activity *BranchActivity, username string) *go
 		PublishedParsed: activity.LatestTime,
 		Updated:         activity.LatestTime.Format(time.RFC3339),
 		UpdatedParsed:   activity.LatestTime,
-		GUID:            fmt.Sprintf("consolidated-%s-%s-%d", activity.Repo, activity.Branch, activity.LatestTime.Unix()),
+		GUID:            pushGUID("consolidated", activity, username),
 	}
 
 	return consolidatedItem
 }
 
-// createIndividualPushItem creates a single item representing one push to a repository/branch
-func createIndividualPushItem(activity *BranchActivity, username string) *gofeed.Item {
+// pushRepoName returns the name of a push's repository for titles, as owner/repo when it isn't the feed
+// user's, like activityRepo
+func pushRepoName(activity *BranchActivity, username string) string {
+	if activity.Owner != "" && !strings.EqualFold(activity.Owner, username) {
+		return activity.Owner + "/" + activity.Repo
+	}
+	return activity.Repo
+}
+
+// pushGUID returns the GUID of a push item, which is unique to the push's actor and owner like pushKey.
+// They're left out for the feed's user, so single-user feeds' GUIDs don't depend on the input source.
+func pushGUID(kind string, activity *BranchActivity, username string) string {
+	repo := activity.Repo
+	if activity.Owner != "" && !strings.EqualFold(activity.Owner, username) {
+		repo = activity.Owner + "/" + repo
+	}
+	if activity.Actor != "" && !strings.EqualFold(activity.Actor, username) {
+		repo = activity.Actor + "@" + repo
+	}
+	return fmt.Sprintf("%s-%s-%s-%d", kind, repo, activity.Branch, activity.LatestTime.Unix())
+}
+
+// createIndividualPushItem creates a single item representing one push to a repository/branch.
+// With conventional set, commits are grouped by Conventional Commits type.
+func createIndividualPushItem(activity *BranchActivity, username string, conventional bool) *gofeed.Item {
 	if len(activity.Commits) == 0 {
 		return nil
 	}
 
-	// Count commits for title
-	commitCount := len(activity.Commits)
+	// Count commits for title, including any GitHub omitted from the feed
+	count := commitCount(activity)
 	commitWord := "commits"
-	if commitCount == 1 {
+	if count == 1 {
 		commitWord = "commit"
 	}
-	title := fmt.Sprintf("%s pushed %d %s to %s/%s", username, commitCount, commitWord, activity.Repo, activity.Branch)
+	actor := username
+	if activity.Actor != "" {
+		actor = activity.Actor
+	}
+	title := fmt.Sprintf("%s pushed %d %s to %s/%s", actor, count, commitWord, pushRepoName(activity, username), activity.Branch)
+	if conventional {
+		if summary := conventionalSummary(activity.Commits); summary != "" {
+			title += fmt.Sprintf(" (%s)", summary)
+		}
+	}
 
 	// Create HTML description with commit details (same format as consolidated)
 	var htmlParts []string
 	htmlParts = append(htmlParts, "<div>")
-
-	for _, commit := range activity.Commits {
-		commitHTML := fmt.Sprintf(
-			"<div style='margin-bottom: 12px;'>"+
-				"<tt><a href='%s'>%s</a></tt>: %s"+
-				"</div>",
-			commit.Link,
-			commit.Hash,
-			commit.Message,
-		)
-		htmlParts = append(htmlParts, commitHTML)
-	}
-
-	// Add compare link if available
-	if activity.CompareLink != "" {
-		htmlParts = append(htmlParts, fmt.Sprintf(
-			"<div style='margin-top: 16px; border-top: 1px solid #eee; padding-top: 8px;'>"+
-				"<a href='%s'>View all changes</a>"+
-				"</div>",
-			activity.CompareLink,
-		))
-	}
+	htmlParts = append(htmlParts, pushDetailsHTML(activity, conventional)...)
 
 	htmlParts = append(htmlParts, "</div>")
 	htmlContent := strings.Join(htmlParts, "")
@@ -557,7 +1625,7 @@ func createIndividualPushItem(activity *BranchActivity, username string) *gofeed
 		PublishedParsed: activity.LatestTime,
 		Updated:         activity.LatestTime.Format(time.RFC3339),
 		UpdatedParsed:   activity.LatestTime,
-		GUID:            fmt.Sprintf("individual-%s-%s-%d", activity.Repo, activity.Branch, activity.LatestTime.Unix()),
+		GUID:            pushGUID("individual", activity, username),
 	}
 
 	return individualItem
@@ -575,6 +1643,24 @@ const (
 	ActivityOther
 )
 
+// String returns the activity type's name, as used in metric labels
+func (t ActivityType) String() string {
+	switch t {
+	case ActivityPullRequest:
+		return "pull_request"
+	case ActivityFork:
+		return "fork"
+	case ActivityBranchCreate:
+		return "branch_create"
+	case ActivityBranchDelete:
+		return "branch_delete"
+	case ActivityTagDelete:
+		return "tag_delete"
+	default:
+		return "other"
+	}
+}
+
 // detectActivityType determines what type of GitHub activity an item represents
 func detectActivityType(item *gofeed.Item) ActivityType {
 	title := strings.ToLower(item.Title)
@@ -599,12 +1685,18 @@ func detectActivityType(item *gofeed.Item) ActivityType {
 }
 
 // simplifyNonCommitItem creates a simplified version of non-commit GitHub activities
-func simplifyNonCommitItem(item *gofeed.Item, username string) *gofeed.Item {
+func simplifyNonCommitItem(item *gofeed.Item, username, host string) *gofeed.Item {
 	activityType := detectActivityType(item)
+	metrics.ActivityItems.Inc(activityType.String())
+	if activityType == ActivityOther {
+		logger.Debug("unrecognized activity; using generic item", "title", item.Title, "link", item.Link)
+	} else {
+		logger.Debug("classified item", "type", activityType.String(), "title", item.Title)
+	}
 
 	switch activityType {
 	case ActivityPullRequest:
-		return simplifyPullRequest(item, username)
+		return simplifyPullRequest(item, username, host)
 	case ActivityFork:
 		return simplifyFork(item, username)
 	case ActivityBranchCreate:
@@ -612,7 +1704,7 @@ func simplifyNonCommitItem(item *gofeed.Item, username string) *gofeed.Item {
 	case ActivityBranchDelete:
 		return simplifyBranchDelete(item, username)
 	case ActivityTagDelete:
-		return simplifyTagDelete(item, username)
+		return simplifyTagDelete(item, username, host)
 	default:
 		// For other activities, create a basic simplified version
 		return simplifyOtherActivity(item, username)
@@ -620,7 +1712,7 @@ func simplifyNonCommitItem(item *gofeed.Item, username string) *gofeed.Item {
 }
 
 // simplifyPullRequest creates a clean, simple pull request entry
-func simplifyPullRequest(item *gofeed.Item, username string) *gofeed.Item {
+func simplifyPullRequest(item *gofeed.Item, username, host string) *gofeed.Item {
 	// Extract PR number and repository from link
 	prNumber := ""
 	targetRepo := ""
@@ -634,7 +1726,7 @@ func simplifyPullRequest(item *gofeed.Item, username string) *gofeed.Item {
 		}
 
 		// Extract repository: github.com/user/repo
-		repoRegex := regexp.MustCompile(`github\.com/([^/]+/[^/]+)`)
+		repoRegex := regexp.MustCompile(regexp.QuoteMeta(host) + `/([^/]+/[^/]+)`)
 		matches = repoRegex.FindStringSubmatch(item.Link)
 		if len(matches) > 1 {
 			targetRepo = matches[1]
@@ -661,8 +1753,14 @@ func simplifyPullRequest(item *gofeed.Item, username string) *gofeed.Item {
 		}
 	}
 
+	return createPullRequestItem(item, username, "opened", prNumber, targetRepo, prTitle, diffStats)
+}
+
+// createPullRequestItem creates a clean pull request entry from extracted fields, taking the link, dates,
+// and GUID from item
+func createPullRequestItem(item *gofeed.Item, username, action, prNumber, targetRepo, prTitle, diffStats string) *gofeed.Item {
 	// Create simplified title
-	title := fmt.Sprintf("%s opened PR #%s in %s", username, prNumber, targetRepo)
+	title := fmt.Sprintf("%s %s PR #%s in %s", username, action, prNumber, targetRepo)
 	if prTitle != "" {
 		title += ": " + prTitle
 	}
@@ -708,6 +1806,11 @@ func simplifyFork(item *gofeed.Item, username string) *gofeed.Item {
 		}
 	}
 
+	return createForkItem(item, username, sourceRepo, targetRepo)
+}
+
+// createForkItem creates a clean fork entry from extracted fields, taking the link, dates, and GUID from item
+func createForkItem(item *gofeed.Item, username, sourceRepo, targetRepo string) *gofeed.Item {
 	title := fmt.Sprintf("%s forked %s", username, sourceRepo)
 
 	htmlContent := `<div style='margin-bottom: 12px;'>`
@@ -753,6 +1856,12 @@ func simplifyBranchCreate(item *gofeed.Item, username string) *gofeed.Item {
 		}
 	}
 
+	return createBranchCreateItem(item, username, branchName, repoName)
+}
+
+// createBranchCreateItem creates a clean branch creation entry from extracted fields, taking the link, dates,
+// and GUID from item
+func createBranchCreateItem(item *gofeed.Item, username, branchName, repoName string) *gofeed.Item {
 	title := fmt.Sprintf("%s created branch %s", username, branchName)
 	if repoName != "" {
 		title += fmt.Sprintf(" in %s", repoName)
@@ -797,7 +1906,7 @@ func simplifyBranchDelete(item *gofeed.Item, username string) *gofeed.Item {
 }
 
 // simplifyTagDelete creates a clean tag deletion entry
-func simplifyTagDelete(item *gofeed.Item, username string) *gofeed.Item {
+func simplifyTagDelete(item *gofeed.Item, username, host string) *gofeed.Item {
 	tagName := ""
 	repoName := ""
 
@@ -840,13 +1949,24 @@ func simplifyTagDelete(item *gofeed.Item, username string) *gofeed.Item {
 
 	// Extract repo from link if not found
 	if repoName == "" && item.Link != "" {
-		repoRegex := regexp.MustCompile(`github\.com/` + regexp.QuoteMeta(username) + `/([^/]+)`)
+		repoRegex := regexp.MustCompile(regexp.QuoteMeta(host) + `/` + regexp.QuoteMeta(username) + `/([^/]+)`)
 		matches := repoRegex.FindStringSubmatch(item.Link)
 		if len(matches) > 1 {
 			repoName = matches[1]
 		}
 	}
 
+	// For deleted tags, link to repo homepage instead of the original link
+	link := item.Link
+	if repoName != "" {
+		link = fmt.Sprintf("%s/%s/%s", hostURL(host), username, repoName)
+	}
+
+	return createTagDeleteItem(item, username, tagName, repoName, link)
+}
+
+// createTagDeleteItem creates a clean tag deletion entry from extracted fields, taking the dates and GUID from item
+func createTagDeleteItem(item *gofeed.Item, username, tagName, repoName, link string) *gofeed.Item {
 	if tagName == "" {
 		tagName = "tag"
 	}
@@ -863,12 +1983,6 @@ func simplifyTagDelete(item *gofeed.Item, username string) *gofeed.Item {
 	}
 	htmlContent += `</div>`
 
-	// For deleted tags, link to repo homepage instead of the original link
-	link := item.Link
-	if repoName != "" {
-		link = fmt.Sprintf("https://github.com/%s/%s", username, repoName)
-	}
-
 	return &gofeed.Item{
 		Title:           title,
 		Description:     htmlContent,
@@ -911,11 +2025,50 @@ func simplifyOtherActivity(item *gofeed.Item, username string) *gofeed.Item {
 // printUsage prints basic usage information
 func printUsage() {
 	fmt.Fprintf(os.Stderr, "Usage: %s [options] <feed-url>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s -source events-api [options] <username|events-api-url>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s -source git [options] <repository-path>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s daemon [-token-file <path>] [-timeout <duration>] [-retries <n>] [-metrics-addr <addr>] [-verbose] <config.json>\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "       %s -help\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "Options:\n")
+	fmt.Fprintf(os.Stderr, "  -source <source>    Input source: atom, events-api, or git (default: atom)\n")
+	fmt.Fprintf(os.Stderr, "  -provider <name>    Forge the feed comes from: github, gitea, forgejo, or gitlab (default: github)\n")
+	fmt.Fprintf(os.Stderr, "  -link-template <url>  Commit link for the git source, e.g. https://git.example.com/{repo}/commit/{hash}\n")
 	fmt.Fprintf(os.Stderr, "  -retitle <title>    Set custom title for the output feed\n")
-	fmt.Fprintf(os.Stderr, "  -format <format>    Output format: atom, rss, or json (default: atom)\n")
+	fmt.Fprintf(os.Stderr, "  -format <format>    Output format: atom, rss, json, or email (default: atom)\n")
 	fmt.Fprintf(os.Stderr, "  -consolidate-pushes <bool>  Consolidate pushes into single entries (default: true)\n")
+	fmt.Fprintf(os.Stderr, "  -noise <mode>       Handle bot and dependency-update activity: keep, drop, or collapse (default: keep)\n")
+	fmt.Fprintf(os.Stderr, "  -noise-pattern <regex>  Treat pushes whose commit messages all match as automated (repeatable)\n")
+	fmt.Fprintf(os.Stderr, "  -push-window <duration>  Only consolidate pushes less than this far apart (default: unlimited; 1h for commits feeds)\n")
+	fmt.Fprintf(os.Stderr, "  -session-window <duration>  Combine pushes to several repositories less than this far apart into one entry\n")
+	fmt.Fprintf(os.Stderr, "  -since <when>       Only include activity since a date, time, or duration ago (e.g. 2025-09-01 or 72h)\n")
+	fmt.Fprintf(os.Stderr, "  -until <when>       Only include activity before a date, time, or duration ago\n")
+	fmt.Fprintf(os.Stderr, "  -max-items <n>      Only include the n most recent items\n")
+	fmt.Fprintf(os.Stderr, "  -digest <period>    Roll items up into one item per day or week: daily or weekly\n")
+	fmt.Fprintf(os.Stderr, "  -match <regex>      Only include commits whose messages match\n")
+	fmt.Fprintf(os.Stderr, "  -exclude-match <regex>  Leave out commits whose messages match\n")
+	fmt.Fprintf(os.Stderr, "  -autolink <bool>    Link issue references like #123 and owner/repo#45 (default: false)\n")
+	fmt.Fprintf(os.Stderr, "  -tracker <regex=url>  Link external tracker keys, e.g. '[A-Z]+-[0-9]+=https://jira.example.com/browse/${0}' (repeatable)\n")
+	fmt.Fprintf(os.Stderr, "  -conventional-commits <bool>  Group commits by Conventional Commits type (default: false)\n")
+	fmt.Fprintf(os.Stderr, "  -github-host <host>  GitHub Enterprise hostname (default: detected from feed)\n")
+	fmt.Fprintf(os.Stderr, "  -token-file <path>  Read an access token for private feeds from a file (default: $GHFEED_TOKEN)\n")
+	fmt.Fprintf(os.Stderr, "  -timeout <duration>  Total time allowed for fetching the feed, including retries (default: 30s)\n")
+	fmt.Fprintf(os.Stderr, "  -retries <n>        Retries on network errors, 5xx, and 429 responses (default: 3)\n")
+	fmt.Fprintf(os.Stderr, "  -stale-cache <path>  Save each feed here and serve it if fetching fails (exit status %d)\n", exitStale)
+	fmt.Fprintf(os.Stderr, "  -stale-item <bool>  Add a \"feed temporarily stale\" item when serving a stale feed (default: false)\n")
+	fmt.Fprintf(os.Stderr, "  -webhook <url>      Also post new items to a Slack, Discord, or Mattermost incoming webhook\n")
+	fmt.Fprintf(os.Stderr, "  -webhook-type <type>  Webhook payload format: slack, discord, or mattermost (default: slack)\n")
+	fmt.Fprintf(os.Stderr, "  -webhook-state <path>  File recording what the webhook has been sent (required with -webhook)\n")
+	fmt.Fprintf(os.Stderr, "  -email-from <addr>  Sender of -format email messages\n")
+	fmt.Fprintf(os.Stderr, "  -email-to <addr>    Recipients of -format email messages (repeatable)\n")
+	fmt.Fprintf(os.Stderr, "  -mbox <path>        Append -format email messages to an mbox file instead of printing them\n")
+	fmt.Fprintf(os.Stderr, "  -smtp <host:port>   Send -format email messages through an SMTP server instead of printing them\n")
+	fmt.Fprintf(os.Stderr, "  -smtp-user <name>   SMTP username; the password is read from -smtp-password-file or $GHFEED_SMTP_PASSWORD\n")
+	fmt.Fprintf(os.Stderr, "  -smtp-password-file <path>  Read the SMTP password from a file\n")
+	fmt.Fprintf(os.Stderr, "  -unparseable <mode>  Report items that fell back to degraded parsing: ignore, warn, or fail (exit status %d) (default: ignore)\n", exitUnparseable)
+	fmt.Fprintf(os.Stderr, "  -explain <format>   Instead of the feed, report how each input item was transformed, as text or json\n")
+	fmt.Fprintf(os.Stderr, "  -verbose            Log how each item was classified and parsed, and why items were dropped\n")
+	fmt.Fprintf(os.Stderr, "  -log-level <level>  Log level: debug, info, warn, or error (default: warn; info for daemon)\n")
+	fmt.Fprintf(os.Stderr, "  -log-format <format>  Log format on stderr: text or json (default: text)\n")
 }
 
 func printVersion() {
@@ -928,12 +2081,55 @@ func printHelp() {
 	fmt.Printf("version %s\n\n", version)
 
 	fmt.Printf("USAGE:\n")
-	fmt.Printf("  %s [options] <feed-url>\n\n", os.Args[0])
+	fmt.Printf("  %s [options] <feed-url>\n", os.Args[0])
+	fmt.Printf("  %s -source events-api [options] <username|events-api-url>\n", os.Args[0])
+	fmt.Printf("  %s -source git [options] <repository-path>\n", os.Args[0])
+	fmt.Printf("  %s daemon [-token-file <path>] [-timeout <duration>] [-retries <n>] [-metrics-addr <addr>] [-verbose] <config.json>\n\n", os.Args[0])
 
 	fmt.Printf("OPTIONS:\n")
+	fmt.Printf("  -source <source>    Input source: atom, events-api, or git (default: atom)\n")
+	fmt.Printf("  -provider <name>    Forge the feed comes from: github, gitea, forgejo, or gitlab (default: github)\n")
+	fmt.Printf("  -link-template <url>  Commit link for the git source, e.g. https://git.example.com/{repo}/commit/{hash}\n")
 	fmt.Printf("  -retitle <title>    Set custom title for the output feed\n")
-	fmt.Printf("  -format <format>    Output format: atom, rss, or json (default: atom)\n")
-	fmt.Printf("  -consolidate-pushes <bool>  Consolidate pushes into single entries (default: true)\n\n")
+	fmt.Printf("  -format <format>    Output format: atom, rss, json, or email (default: atom)\n")
+	fmt.Printf("  -consolidate-pushes <bool>  Consolidate pushes into single entries (default: true)\n")
+	fmt.Printf("  -noise <mode>       Handle bot and dependency-update activity: keep, drop, or collapse (default: keep)\n")
+	fmt.Printf("  -noise-pattern <regex>  Treat pushes whose commit messages all match as automated (repeatable)\n")
+	fmt.Printf("  -push-window <duration>  Only consolidate pushes less than this far apart (default: unlimited; 1h for commits feeds)\n")
+	fmt.Printf("  -session-window <duration>  Combine pushes to several repositories less than this far apart into one entry\n")
+	fmt.Printf("  -since <when>       Only include activity since a date, time, or duration ago (e.g. 2025-09-01 or 72h)\n")
+	fmt.Printf("  -until <when>       Only include activity before a date, time, or duration ago\n")
+	fmt.Printf("  -max-items <n>      Only include the n most recent items\n")
+	fmt.Printf("  -digest <period>    Roll items up into one item per day or week: daily or weekly\n")
+	fmt.Printf("  -match <regex>      Only include commits whose messages match\n")
+	fmt.Printf("  -exclude-match <regex>  Leave out commits whose messages match\n")
+	fmt.Printf("  -autolink <bool>    Link issue references like #123 and owner/repo#45 (default: false)\n")
+	fmt.Printf("  -tracker <regex=url>  Link external tracker keys, e.g. '[A-Z]+-[0-9]+=https://jira.example.com/browse/${0}' (repeatable)\n")
+	fmt.Printf("  -conventional-commits <bool>  Group commits by Conventional Commits type (default: false)\n")
+	fmt.Printf("  -github-host <host>  GitHub Enterprise hostname (default: detected from feed)\n")
+	fmt.Printf("  -token-file <path>  Read an access token for private feeds from a file (default: $GHFEED_TOKEN)\n")
+	fmt.Printf("  -timeout <duration>  Total time allowed for fetching the feed, including retries (default: 30s)\n")
+	fmt.Printf("  -retries <n>        Retries on network errors, 5xx, and 429 responses (default: 3)\n")
+	fmt.Printf("  -stale-cache <path>  Save each feed here and serve it if fetching fails (exit status %d)\n", exitStale)
+	fmt.Printf("  -stale-item <bool>  Add a \"feed temporarily stale\" item when serving a stale feed (default: false)\n")
+	fmt.Printf("  -webhook <url>      Also post new items to a Slack, Discord, or Mattermost incoming webhook\n")
+	fmt.Printf("  -webhook-type <type>  Webhook payload format: slack, discord, or mattermost (default: slack)\n")
+	fmt.Printf("  -webhook-state <path>  File recording what the webhook has been sent (required with -webhook)\n")
+	fmt.Printf("  -email-from <addr>  Sender of -format email messages\n")
+	fmt.Printf("  -email-to <addr>    Recipients of -format email messages (repeatable)\n")
+	fmt.Printf("  -mbox <path>        Append -format email messages to an mbox file instead of printing them\n")
+	fmt.Printf("  -smtp <host:port>   Send -format email messages through an SMTP server instead of printing them\n")
+	fmt.Printf("  -smtp-user <name>   SMTP username; the password is read from -smtp-password-file or $GHFEED_SMTP_PASSWORD\n")
+	fmt.Printf("  -smtp-password-file <path>  Read the SMTP password from a file\n")
+	fmt.Printf("  -unparseable <mode>  Report items that fell back to degraded parsing: ignore, warn, or fail (exit status %d) (default: ignore)\n", exitUnparseable)
+	fmt.Printf("  -explain <format>   Instead of the feed, report how each input item was transformed, as text or json\n")
+	fmt.Printf("  -verbose            Log how each item was classified and parsed, and why items were dropped\n")
+	fmt.Printf("  -log-level <level>  Log level: debug, info, warn, or error (default: warn; info for daemon)\n")
+	fmt.Printf("  -log-format <format>  Log format on stderr: text or json (default: text)\n\n")
+
+	fmt.Printf("ENVIRONMENT:\n")
+	fmt.Printf("  GHFEED_TOKEN        Access token for private feeds, used when -token-file is not given\n")
+	fmt.Printf("  GHFEED_SMTP_PASSWORD  SMTP password, used when -smtp-password-file is not given\n\n")
 
 	fmt.Printf("DESCRIPTION:\n")
 	fmt.Printf("  Transforms verbose GitHub Atom feeds into clean, readable summaries.\n")
@@ -951,7 +2147,15 @@ func printHelp() {
 	fmt.Printf("  %s https://github.com/username.atom\n", os.Args[0])
 	fmt.Printf("  %s -retitle \"My Custom Feed\" https://github.com/username.atom\n", os.Args[0])
 	fmt.Printf("  %s -format rss https://github.com/username.atom\n", os.Args[0])
-	fmt.Printf("  %s -format json -retitle \"JSON Feed\" https://github.com/username.atom\n\n", os.Args[0])
+	fmt.Printf("  %s -format json -retitle \"JSON Feed\" https://github.com/username.atom\n", os.Args[0])
+	fmt.Printf("  %s -format email -email-from ghfeed@example.com -email-to team@example.com -smtp smtp.example.com:587 https://github.com/username.atom\n", os.Args[0])
+	fmt.Printf("  %s -github-host github.example.com https://github.example.com/username.atom\n", os.Args[0])
+	fmt.Printf("  %s -token-file ~/.ghfeed-token https://github.com/username.private.atom\n", os.Args[0])
+	fmt.Printf("  %s -source events-api username\n", os.Args[0])
+	fmt.Printf("  %s https://github.com/owner/repo/commits/main.atom\n", os.Args[0])
+	fmt.Printf("  %s https://github.com/orgs/ourorg.atom\n", os.Args[0])
+	fmt.Printf("  %s -provider gitlab https://gitlab.com/username.atom\n", os.Args[0])
+	fmt.Printf("  %s -source git -link-template 'https://git.example.com/{repo}/commit/{hash}' /srv/git/repo.git\n\n", os.Args[0])
 
 	fmt.Printf("AUTHOR:\n")
 	fmt.Printf("  Chris Dzombak: https://dzombak.com, https://github.com/cdzombak\n\n")
@@ -227,7 +227,7 @@ func TestExtractCommitsFromContent(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := extractCommitsFromContent(tt.content)
+			result := extractCommitsFromContent(tt.content, "github.com")
 
 			if len(result) != len(tt.expected) {
 				t.Errorf("extractCommitsFromContent() = %d commits, want %d", len(result), len(tt.expected))
@@ -311,7 +311,7 @@ func TestExtractUsername(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := extractUsername(tt.feed)
+			result := extractUsername(tt.feed, "github.com")
 			if result != tt.expected {
 				t.Errorf("extractUsername() = %v, want %v", result, tt.expected)
 			}
@@ -449,7 +449,7 @@ func TestSimplifyPullRequest(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := simplifyPullRequest(tt.item, "cdzombak")
+			result := simplifyPullRequest(tt.item, "cdzombak", "github.com")
 
 			if result.Title != tt.expected.title {
 				t.Errorf("simplifyPullRequest().Title = %v, want %v", result.Title, tt.expected.title)
@@ -570,7 +570,7 @@ func TestSimplifyTagDelete(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := simplifyTagDelete(tt.item, "cdzombak")
+			result := simplifyTagDelete(tt.item, "cdzombak", "github.com")
 
 			if result.Title != tt.expected.title {
 				t.Errorf("simplifyTagDelete().Title = %v, want %v", result.Title, tt.expected.title)
@@ -631,7 +631,7 @@ func TestExtractBranchActivity(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := extractBranchActivity(tt.item, "cdzombak")
+			result := extractBranchActivity(tt.item, "cdzombak", "github.com")
 
 			if tt.expected == nil {
 				if result != nil {
@@ -696,7 +696,7 @@ func TestCreateConsolidatedBranchItem(t *testing.T) {
 		CompareLink: "https://github.com/cdzombak/dotfiles/compare/b19a1b604e...8e9b024bed",
 	}
 
-	result := createConsolidatedBranchItem(activity, "cdzombak")
+	result := createConsolidatedBranchItem(activity, "cdzombak", false)
 
 	expectedTitle := "cdzombak pushed 2 commits to dotfiles/master"
 	if result.Title != expectedTitle {
@@ -743,7 +743,7 @@ func TestCreateConsolidatedBranchItemSingleCommit(t *testing.T) {
 		CompareLink: "https://github.com/cdzombak/dotfiles/compare/b19a1b604e...8e9b024bed",
 	}
 
-	result := createConsolidatedBranchItem(activity, "cdzombak")
+	result := createConsolidatedBranchItem(activity, "cdzombak", false)
 
 	expectedTitle := "cdzombak pushed 1 commit to dotfiles/master"
 	if result.Title != expectedTitle {
@@ -763,7 +763,7 @@ func TestCreateConsolidatedBranchItemNoCommits(t *testing.T) {
 		Commits: []Commit{},
 	}
 
-	result := createConsolidatedBranchItem(activity, "cdzombak")
+	result := createConsolidatedBranchItem(activity, "cdzombak", false)
 
 	if result != nil {
 		t.Errorf("createConsolidatedBranchItem() with no commits = %v, want nil", result)
@@ -848,7 +848,7 @@ func TestConsolidateCommitsIntegration(t *testing.T) {
 		},
 	}
 
-	result := consolidateCommits(inputFeed, "", true)
+	result := consolidateCommits(inputFeed, Options{ConsolidatePushes: true})
 
 	// Verify feed metadata is preserved
 	if result.Title != inputFeed.Title {
@@ -979,7 +979,7 @@ func TestBranchActivityMerging(t *testing.T) {
 		},
 	}
 
-	result := consolidateCommits(inputFeed, "", true)
+	result := consolidateCommits(inputFeed, Options{ConsolidatePushes: true})
 
 	// Should have 1 consolidated item (both pushes merged)
 	if len(result.Items) != 1 {
@@ -1044,10 +1044,10 @@ func TestDifferentUsernames(t *testing.T) {
 				},
 			}
 
-			result := consolidateCommits(inputFeed, "", true)
+			result := consolidateCommits(inputFeed, Options{ConsolidatePushes: true})
 
 			// Verify username extraction worked
-			extractedUsername := extractUsername(inputFeed)
+			extractedUsername := extractUsername(inputFeed, "github.com")
 			if extractedUsername != username {
 				t.Errorf("extractUsername() = %v, want %v", extractedUsername, username)
 			}
@@ -1112,7 +1112,7 @@ func TestSimplifyFunctionsWithDifferentUsers(t *testing.T) {
 				Link:    "https://github.com/test/repo/pull/123",
 			}
 
-			result := simplifyPullRequest(prItem, username)
+			result := simplifyPullRequest(prItem, username, "github.com")
 			expectedTitle := username + " opened PR #123 in test/repo: Test PR Title"
 			if result.Title != expectedTitle {
 				t.Errorf("simplifyPullRequest title = %v, want %v", result.Title, expectedTitle)
@@ -1150,7 +1150,7 @@ func TestSimplifyFunctionsWithDifferentUsers(t *testing.T) {
 				Link:    "https://github.com/" + username + "/repo/compare/abc123...000000",
 			}
 
-			tagResult := simplifyTagDelete(tagItem, username)
+			tagResult := simplifyTagDelete(tagItem, username, "github.com")
 			expectedTagTitle := username + " deleted tag refs/tags/v1.0.0 in repo"
 			if tagResult.Title != expectedTagTitle {
 				t.Errorf("simplifyTagDelete title = %v, want %v", tagResult.Title, expectedTagTitle)
@@ -1203,7 +1203,7 @@ func TestHTMLParsingEdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			commits := extractCommitsFromContent(tt.content)
+			commits := extractCommitsFromContent(tt.content, "github.com")
 			if len(commits) != tt.expected {
 				t.Errorf("extractCommitsFromContent() = %d commits, want %d", len(commits), tt.expected)
 			}
@@ -1330,7 +1330,7 @@ func TestGenerateComparisonLink(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := generateComparisonLink(tt.activity, tt.username)
+			result := generateComparisonLink(tt.activity, tt.username, "github.com", providerGitHub)
 			if result != tt.expected {
 				t.Errorf("generateComparisonLink() = %v, want %v", result, tt.expected)
 			}
@@ -1373,7 +1373,7 @@ func TestConsolidateCommitsWithConsolidationDisabled(t *testing.T) {
 	}
 
 	// Test with consolidation disabled
-	result := consolidateCommits(inputFeed, "", false)
+	result := consolidateCommits(inputFeed, Options{ConsolidatePushes: false})
 
 	// Should have 2 separate push items (not consolidated)
 	if len(result.Items) != 2 {
@@ -1428,7 +1428,7 @@ func TestConsolidateCommitsWithConsolidationEnabled(t *testing.T) {
 	}
 
 	// Test with consolidation enabled (default behavior)
-	result := consolidateCommits(inputFeed, "", true)
+	result := consolidateCommits(inputFeed, Options{ConsolidatePushes: true})
 
 	// Should have 1 consolidated item
 	if len(result.Items) != 1 {
@@ -1483,9 +1483,9 @@ func TestCommitOrderingWithConsolidationDisabled(t *testing.T) {
 
 	for _, item := range items {
 		if isCommitOrPush(item.Title) {
-			activity := extractBranchActivity(item, username)
+			activity := extractBranchActivity(item, username, "github.com")
 			activities = append(activities, activity)
-			result = append(result, createIndividualPushItem(activity, username))
+			result = append(result, createIndividualPushItem(activity, username, false))
 		}
 	}
 
@@ -1566,7 +1566,7 @@ func TestCreateIndividualPushItem(t *testing.T) {
 		CompareLink: "https://github.com/cdzombak/dotfiles/compare/b19a1b6^...8e9b024",
 	}
 
-	result := createIndividualPushItem(activity, "cdzombak")
+	result := createIndividualPushItem(activity, "cdzombak", false)
 
 	if result == nil {
 		t.Fatal("createIndividualPushItem() returned nil")
@@ -1621,7 +1621,7 @@ func TestOriginalCompareLinkPreservation(t *testing.T) {
 	}
 
 	// Test with consolidation disabled - should preserve original compare link
-	result := consolidateCommits(inputFeed, "", false)
+	result := consolidateCommits(inputFeed, Options{ConsolidatePushes: false})
 
 	if len(result.Items) != 1 {
 		t.Fatalf("Expected 1 item when consolidation disabled, got %d", len(result.Items))
@@ -1640,7 +1640,7 @@ func TestOriginalCompareLinkPreservation(t *testing.T) {
 	}
 
 	// When consolidation is enabled, comparison links may be generated (this behavior is expected)
-	resultConsolidated := consolidateCommits(inputFeed, "", true)
+	resultConsolidated := consolidateCommits(inputFeed, Options{ConsolidatePushes: true})
 
 	if len(resultConsolidated.Items) != 1 {
 		t.Fatalf("Expected 1 item when consolidation enabled, got %d", len(resultConsolidated.Items))
@@ -1653,3 +1653,357 @@ func TestOriginalCompareLinkPreservation(t *testing.T) {
 		t.Errorf("Consolidated item link should reference correct repository, got %v", consolidatedItem.Link)
 	}
 }
+
+// truncatedPushHTML is a push where GitHub lists only some commits followed by a "more commits" link
+const truncatedPushHTML = `<div class="commits pusher-is-only-committer">
+      <ul class="list-style-none">
+          <li class="d-flex flex-items-baseline">
+            <code><a class="mr-1" href="/cdzombak/dotfiles/commit/8e9b024bede1064de870417f7e3f7aa876fa3b47" rel="noreferrer">8e9b024</a></code>
+            <div class="dashboard-break-word lh-condensed">
+              <blockquote>
+                remove Instapaper Save app
+              </blockquote>
+            </div>
+          </li>
+          <li class="d-flex flex-items-baseline">
+            <code><a class="mr-1" href="/cdzombak/dotfiles/commit/b19a1b604e77908604438ab33529c6a6a9d7f9d1" rel="noreferrer">b19a1b6</a></code>
+            <div class="dashboard-break-word lh-condensed">
+              <blockquote>
+                fix Red Eye install
+              </blockquote>
+            </div>
+          </li>
+          <li class="f6 mt-2">
+            <a class="Link--secondary" href="/cdzombak/dotfiles/compare/1a2b3c4d5e...8e9b024bed" rel="noreferrer">38 more commits »</a>
+          </li>
+      </ul>
+    </div>`
+
+func TestExtractMoreCommits(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedCount int
+		expectedLink  string
+	}{
+		{
+			name:          "Truncated push",
+			content:       truncatedPushHTML,
+			expectedCount: 38,
+			expectedLink:  "https://github.com/cdzombak/dotfiles/compare/1a2b3c4d5e...8e9b024bed",
+		},
+		{
+			name:          "Large count with thousands separator",
+			content:       `<a href="/cdzombak/big/compare/aaa...bbb">1,204 more commits &raquo;</a>`,
+			expectedCount: 1204,
+			expectedLink:  "https://github.com/cdzombak/big/compare/aaa...bbb",
+		},
+		{
+			name:          "Single more commit",
+			content:       `<a href="/cdzombak/small/compare/aaa...bbb">1 more commit »</a>`,
+			expectedCount: 1,
+			expectedLink:  "https://github.com/cdzombak/small/compare/aaa...bbb",
+		},
+		{
+			name:          "Untruncated push",
+			content:       pushHTML,
+			expectedCount: 0,
+			expectedLink:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, link := extractMoreCommits(tt.content, "github.com")
+			if count != tt.expectedCount {
+				t.Errorf("extractMoreCommits() count = %d, want %d", count, tt.expectedCount)
+			}
+			if link != tt.expectedLink {
+				t.Errorf("extractMoreCommits() link = %v, want %v", link, tt.expectedLink)
+			}
+		})
+	}
+}
+
+func TestTruncatedPushConsolidation(t *testing.T) {
+	publishedTime, _ := time.Parse(time.RFC3339, "2025-09-15T01:28:02Z")
+	moreLink := "https://github.com/cdzombak/dotfiles/compare/1a2b3c4d5e...8e9b024bed"
+
+	item := &gofeed.Item{
+		Title:           "cdzombak pushed dotfiles",
+		Content:         truncatedPushHTML,
+		Link:            "https://github.com/cdzombak/dotfiles/compare/1a2b3c4d5e...8e9b024bed",
+		PublishedParsed: &publishedTime,
+	}
+
+	activity := extractBranchActivity(item, "cdzombak", "github.com")
+	if activity == nil {
+		t.Fatal("extractBranchActivity() = nil, want non-nil")
+	}
+	if len(activity.Commits) != 2 {
+		t.Errorf("extractBranchActivity().Commits length = %d, want 2", len(activity.Commits))
+	}
+	if activity.TotalCommits != 40 {
+		t.Errorf("extractBranchActivity().TotalCommits = %d, want 40", activity.TotalCommits)
+	}
+	if activity.MoreCommitsLink != moreLink {
+		t.Errorf("extractBranchActivity().MoreCommitsLink = %v, want %v", activity.MoreCommitsLink, moreLink)
+	}
+
+	// The visible commits don't span the push, so the compare link should be GitHub's own
+	if link := generateComparisonLink(activity, "cdzombak", "github.com", providerGitHub); link != moreLink {
+		t.Errorf("generateComparisonLink() = %v, want %v", link, moreLink)
+	}
+
+	inputFeed := &gofeed.Feed{
+		Title: "cdzombak's Activity",
+		Link:  "https://github.com/cdzombak.atom",
+		Items: []*gofeed.Item{item},
+	}
+
+	for _, consolidate := range []bool{true, false} {
+		result := consolidateCommits(inputFeed, Options{ConsolidatePushes: consolidate})
+		if len(result.Items) != 1 {
+			t.Fatalf("consolidateCommits(consolidate=%v) items count = %d, want 1", consolidate, len(result.Items))
+		}
+
+		expectedTitle := "cdzombak pushed 40 commits to dotfiles/master"
+		if result.Items[0].Title != expectedTitle {
+			t.Errorf("consolidateCommits(consolidate=%v) title = %v, want %v", consolidate, result.Items[0].Title, expectedTitle)
+		}
+		if !strings.Contains(result.Items[0].Content, "38 more commits") {
+			t.Errorf("consolidateCommits(consolidate=%v) content missing more commits note, got %v", consolidate, result.Items[0].Content)
+		}
+		if !strings.Contains(result.Items[0].Content, moreLink) {
+			t.Errorf("consolidateCommits(consolidate=%v) content missing full compare link, got %v", consolidate, result.Items[0].Content)
+		}
+	}
+}
+
+func TestNormalizeHost(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"github.example.com", "github.example.com"},
+		{"https://github.example.com/", "github.example.com"},
+		{"http://GitHub.Example.com", "github.example.com"},
+		{"https://github.example.com/some/path", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := normalizeHost(tt.input); result != tt.expected {
+				t.Errorf("normalizeHost(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetectHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		feed     *gofeed.Feed
+		expected string
+	}{
+		{
+			name:     "github.com feed",
+			feed:     &gofeed.Feed{Link: "https://github.com/cdzombak"},
+			expected: "github.com",
+		},
+		{
+			name:     "Enterprise feed",
+			feed:     &gofeed.Feed{Link: "https://git.corp.example.com/cdzombak"},
+			expected: "git.corp.example.com",
+		},
+		{
+			name:     "Falls back to feed link",
+			feed:     &gofeed.Feed{FeedLink: "https://git.corp.example.com/cdzombak.atom"},
+			expected: "git.corp.example.com",
+		},
+		{
+			name:     "No links",
+			feed:     &gofeed.Feed{},
+			expected: "github.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := detectHost(tt.feed); result != tt.expected {
+				t.Errorf("detectHost() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConsolidateCommitsEnterpriseHost(t *testing.T) {
+	publishedTime, _ := time.Parse(time.RFC3339, "2025-09-15T01:28:02Z")
+	host := "git.corp.example.com"
+
+	inputFeed := &gofeed.Feed{
+		Title: "cdzombak's Activity",
+		Link:  "https://" + host + "/cdzombak",
+		Items: []*gofeed.Item{
+			{
+				Title:           "cdzombak pushed dotfiles",
+				Content:         pushHTML,
+				Link:            "https://" + host + "/cdzombak/dotfiles/compare/b19a1b604e...8e9b024bed",
+				PublishedParsed: &publishedTime,
+			},
+			{
+				Title:           "cdzombak opened a pull request in mmcdole/gofeed",
+				Content:         pullRequestHTML,
+				Link:            "https://" + host + "/mmcdole/gofeed/pull/264",
+				PublishedParsed: &publishedTime,
+			},
+		},
+	}
+
+	// The same feed should be handled whether the host is detected or configured
+	for _, opts := range []Options{
+		{ConsolidatePushes: true},
+		{ConsolidatePushes: true, Host: host},
+	} {
+		result := consolidateCommits(inputFeed, opts)
+		if len(result.Items) != 2 {
+			t.Fatalf("consolidateCommits(Host=%q) items count = %d, want 2", opts.Host, len(result.Items))
+		}
+
+		var pushItem, prItem *gofeed.Item
+		for _, item := range result.Items {
+			if strings.Contains(item.Title, "pushed") {
+				pushItem = item
+			} else {
+				prItem = item
+			}
+		}
+
+		if pushItem == nil {
+			t.Fatalf("consolidateCommits(Host=%q) missing push item", opts.Host)
+		}
+		expectedTitle := "cdzombak pushed 2 commits to dotfiles/master"
+		if pushItem.Title != expectedTitle {
+			t.Errorf("consolidateCommits(Host=%q) push title = %v, want %v", opts.Host, pushItem.Title, expectedTitle)
+		}
+		expectedLink := "https://" + host + "/cdzombak/dotfiles/compare/8e9b024bede1064de870417f7e3f7aa876fa3b47^...b19a1b604e77908604438ab33529c6a6a9d7f9d1"
+		if pushItem.Link != expectedLink {
+			t.Errorf("consolidateCommits(Host=%q) push link = %v, want %v", opts.Host, pushItem.Link, expectedLink)
+		}
+		if strings.Contains(pushItem.Content, "https://github.com/") {
+			t.Errorf("consolidateCommits(Host=%q) push content links to github.com: %v", opts.Host, pushItem.Content)
+		}
+
+		if prItem == nil {
+			t.Fatalf("consolidateCommits(Host=%q) missing PR item", opts.Host)
+		}
+		if !strings.HasPrefix(prItem.Title, "cdzombak opened PR #264 in mmcdole/gofeed") {
+			t.Errorf("consolidateCommits(Host=%q) PR title = %v", opts.Host, prItem.Title)
+		}
+	}
+}
+
+func TestItemActor(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     *gofeed.Item
+		expected string
+	}{
+		{
+			name:     "Author",
+			item:     &gofeed.Item{Title: "octocat pushed hello-world", Authors: []*gofeed.Person{{Name: "octocat"}}},
+			expected: "octocat",
+		},
+		{
+			name:     "Title",
+			item:     &gofeed.Item{Title: "dependabot[bot] opened a pull request in cdzombak/ghfeed"},
+			expected: "dependabot[bot]",
+		},
+		{
+			name:     "Author with display name",
+			item:     &gofeed.Item{Title: "octocat starred cdzombak/ghfeed", Authors: []*gofeed.Person{{Name: "The Octocat"}}},
+			expected: "octocat",
+		},
+		{
+			name:     "Fallback",
+			item:     &gofeed.Item{Title: "Something happened!"},
+			expected: "cdzombak",
+		},
+		{
+			name:     "Feed user in another case",
+			item:     &gofeed.Item{Title: "CDZombak pushed dotfiles", Authors: []*gofeed.Person{{Name: "CDZombak"}}},
+			expected: "cdzombak",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := itemActor(tt.item, "cdzombak"); got != tt.expected {
+				t.Errorf("itemActor() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConsolidateCommitsTeamFeed(t *testing.T) {
+	at := func(hour int) *time.Time {
+		t := time.Date(2025, 9, 15, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	push := func(actor, owner, hash string, hour int) *gofeed.Item {
+		return &gofeed.Item{
+			Title:           actor + " pushed dotfiles",
+			Link:            "https://github.com/" + owner + "/dotfiles/compare/a0a0a0a0a0..." + hash,
+			Content:         `<a class="branch-name" href="/` + owner + `/dotfiles/tree/main">main</a><code><a href="/` + owner + `/dotfiles/commit/` + hash + `">` + hash[:7] + `</a></code><div><blockquote>Update</blockquote></div>`,
+			Authors:         []*gofeed.Person{{Name: actor}},
+			PublishedParsed: at(hour),
+		}
+	}
+
+	feed := &gofeed.Feed{
+		Link: "https://github.com/cdzombak",
+		Items: []*gofeed.Item{
+			push("octocat", "cdzombak", "4444444444", 4),
+			push("cdzombak", "cdzombak", "3333333333", 3),
+			push("octocat", "octocat", "2222222222", 2),
+			{
+				Title:           "octocat opened a pull request in cdzombak/ghfeed",
+				Link:            "https://github.com/cdzombak/ghfeed/pull/12",
+				Authors:         []*gofeed.Person{{Name: "octocat"}},
+				PublishedParsed: at(1),
+			},
+		},
+	}
+	result := consolidateCommits(feed, Options{ConsolidatePushes: true})
+
+	expected := []string{
+		"octocat pushed 1 commit to dotfiles/main",
+		"cdzombak pushed 1 commit to dotfiles/main",
+		"octocat pushed 1 commit to octocat/dotfiles/main",
+		"octocat opened PR #12 in cdzombak/ghfeed",
+	}
+	if len(result.Items) != len(expected) {
+		t.Fatalf("consolidateCommits() items count = %d, want %d", len(result.Items), len(expected))
+	}
+	for i, want := range expected {
+		if result.Items[i].Title != want {
+			t.Errorf("consolidateCommits() item %d title = %v, want %v", i, result.Items[i].Title, want)
+		}
+	}
+	// octocat's push to their own dotfiles links there
+	if !strings.HasPrefix(result.Items[2].Link, "https://github.com/octocat/dotfiles/") {
+		t.Errorf("consolidateCommits() octocat's own push link = %v", result.Items[2].Link)
+	}
+
+	// Pushes by different actors and owners to the same repository name and branch get their own GUIDs
+	expectedGUIDs := []string{
+		"consolidated-octocat@dotfiles-main-1757908800",
+		"consolidated-dotfiles-main-1757905200",
+		"consolidated-octocat@octocat/dotfiles-main-1757901600",
+	}
+	for i, want := range expectedGUIDs {
+		if result.Items[i].GUID != want {
+			t.Errorf("consolidateCommits() item %d GUID = %v, want %v", i, result.Items[i].GUID, want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import "regexp"
+
+// filterCommits keeps only the commits whose messages match match (when set) and don't match exclude
+// (when set), dropping pushes left with no commits. Other activity is unaffected.
+func filterCommits(activities []Activity, match, exclude *regexp.Regexp, username, host, provider string) []Activity {
+	if match == nil && exclude == nil {
+		return activities
+	}
+
+	kept := []Activity{}
+	for _, activity := range activities {
+		push := activity.Push
+		if push == nil {
+			kept = append(kept, activity)
+			continue
+		}
+
+		var commits []Commit
+		for _, commit := range push.Commits {
+			if match != nil && !match.MatchString(commit.Message) {
+				continue
+			}
+			if exclude != nil && exclude.MatchString(commit.Message) {
+				continue
+			}
+			commits = append(commits, commit)
+		}
+		if len(commits) == 0 {
+			logger.Debug("dropped push with no matching commits", activityAttrs(activity, username)...)
+			continue
+		}
+
+		if len(commits) < len(push.Commits) || commitCount(push) > len(push.Commits) {
+			// Commits omitted from the feed can't be matched, so the push is now just the listed commits that
+			// remain; link to those rather than the whole push
+			filtered := *push
+			filtered.Commits = commits
+			filtered.TotalCommits = len(commits)
+			filtered.MoreCommitsLink = ""
+			filtered.CompareLink = generateComparisonLink(&filtered, username, host, provider)
+			push = &filtered
+		}
+		kept = append(kept, Activity{Push: push})
+	}
+	return kept
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestFilterCommits(t *testing.T) {
+	pushed := time.Date(2025, 9, 15, 1, 28, 2, 0, time.UTC)
+	activities := func() []Activity {
+		return []Activity{
+			{Push: &BranchActivity{
+				Repo:   "ghfeed",
+				Branch: "main",
+				Commits: []Commit{
+					{Hash: "ccccccc", Message: "WIP", Link: "https://github.com/cdzombak/ghfeed/commit/ccccccc"},
+					{Hash: "bbbbbbb", Message: "JIRA-123: handle empty feeds", Link: "https://github.com/cdzombak/ghfeed/commit/bbbbbbb"},
+					{Hash: "aaaaaaa", Message: "Add tests for JIRA-123", Link: "https://github.com/cdzombak/ghfeed/commit/aaaaaaa"},
+				},
+				LatestTime:      &pushed,
+				CompareLink:     "https://github.com/cdzombak/ghfeed/compare/0000000...ccccccc",
+				TotalCommits:    5,
+				MoreCommitsLink: "https://github.com/cdzombak/ghfeed/compare/0000000...ccccccc",
+			}},
+			{Item: &gofeed.Item{Title: "cdzombak starred golang/go", PublishedParsed: &pushed}},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		match         string
+		exclude       string
+		expectedTitle string
+		expectedLink  string
+	}{
+		{
+			name:          "Match",
+			match:         `JIRA-123`,
+			expectedTitle: "cdzombak pushed 2 commits to ghfeed/main",
+			expectedLink:  "https://github.com/cdzombak/ghfeed/compare/aaaaaaa^...bbbbbbb",
+		},
+		{
+			name:          "Exclude",
+			exclude:       `^WIP\b`,
+			expectedTitle: "cdzombak pushed 2 commits to ghfeed/main",
+			expectedLink:  "https://github.com/cdzombak/ghfeed/compare/aaaaaaa^...bbbbbbb",
+		},
+		{
+			name:          "Match and exclude",
+			match:         `JIRA-123`,
+			exclude:       `^Add tests`,
+			expectedTitle: "cdzombak pushed 1 commit to ghfeed/main",
+			expectedLink:  "https://github.com/cdzombak/ghfeed/commit/bbbbbbb",
+		},
+		{
+			name:  "No matches",
+			match: `JIRA-456`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{ConsolidatePushes: true}
+			if tt.match != "" {
+				opts.Match = regexp.MustCompile(tt.match)
+			}
+			if tt.exclude != "" {
+				opts.ExcludeMatch = regexp.MustCompile(tt.exclude)
+			}
+
+			result := consolidateActivities(&gofeed.Feed{}, activities(), "cdzombak", "github.com", opts)
+
+			// Non-push activity is never filtered
+			expectedItems := 1
+			if tt.expectedTitle != "" {
+				expectedItems = 2
+			}
+			if len(result.Items) != expectedItems {
+				t.Fatalf("consolidateActivities() items count = %d, want %d", len(result.Items), expectedItems)
+			}
+			if tt.expectedTitle == "" {
+				return
+			}
+
+			var push *gofeed.Item
+			for _, item := range result.Items {
+				if item.Title != "cdzombak starred golang/go" {
+					push = item
+				}
+			}
+			if push == nil || push.Title != tt.expectedTitle {
+				t.Fatalf("push item = %+v, want title %v", push, tt.expectedTitle)
+			}
+			if push.Link != tt.expectedLink {
+				t.Errorf("push link = %v, want %v", push.Link, tt.expectedLink)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultBuckets are the histogram bucket upper bounds, in seconds, matching Prometheus client defaults
+var defaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics are the counters and histograms exposed at /metrics in the Prometheus text format
+type Metrics struct {
+	FetchDuration       *metricVec
+	FetchResponses      *metricVec
+	ParseFailures       *metricVec
+	ActivityItems       *metricVec
+	CommitsConsolidated *metricVec
+	RenderDuration      *metricVec
+	JobRuns             *metricVec
+	JobLastSuccess      *metricVec
+	families            []*metricVec
+}
+
+// metrics is the process-wide registry, updated by each pipeline stage
+var metrics = newMetrics()
+
+// newMetrics creates the registry of ghfeed's metrics
+func newMetrics() *Metrics {
+	m := &Metrics{
+		FetchDuration:       newMetricVec("ghfeed_fetch_duration_seconds", "Upstream fetch latency per attempt, by HTTP status code (or \"error\").", "histogram", "code"),
+		FetchResponses:      newMetricVec("ghfeed_fetch_responses_total", "Upstream fetch attempts, by HTTP status code (or \"error\").", "counter", "code"),
+		ParseFailures:       newMetricVec("ghfeed_parse_failures_total", "Upstream responses that couldn't be parsed.", "counter"),
+		ActivityItems:       newMetricVec("ghfeed_activity_items_total", "Non-push items by detected activity type; type=\"other\" counts items that fell back to ActivityOther.", "counter", "type"),
+		CommitsConsolidated: newMetricVec("ghfeed_commits_consolidated_total", "Commits included in consolidated push items.", "counter"),
+		RenderDuration:      newMetricVec("ghfeed_render_duration_seconds", "Time to render the output feed, by format.", "histogram", "format"),
+		JobRuns:             newMetricVec("ghfeed_job_runs_total", "Daemon job runs, by job and result (success or failure).", "counter", "job", "result"),
+		JobLastSuccess:      newMetricVec("ghfeed_job_last_success_timestamp_seconds", "Unix time of each daemon job's last successful run.", "gauge", "job"),
+	}
+	m.families = []*metricVec{
+		m.FetchDuration, m.FetchResponses, m.ParseFailures, m.ActivityItems,
+		m.CommitsConsolidated, m.RenderDuration, m.JobRuns, m.JobLastSuccess,
+	}
+	return m
+}
+
+// ServeHTTP writes every metric in the Prometheus text exposition format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.write(w)
+}
+
+// write writes every metric in the Prometheus text exposition format
+func (m *Metrics) write(w io.Writer) {
+	for _, family := range m.families {
+		family.write(w)
+	}
+}
+
+// metricVec is a counter, gauge, or histogram family with a fixed set of label names
+type metricVec struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*metricSeries
+}
+
+// metricSeries is one labeled series' value; histograms also track bucket counts
+type metricSeries struct {
+	labelValues []string
+	value       float64 // counter or gauge value, or histogram sum
+	count       uint64
+	buckets     []uint64
+}
+
+// newMetricVec creates a metric family of the given kind (counter, gauge, or histogram)
+func newMetricVec(name, help, kind string, labels ...string) *metricVec {
+	return &metricVec{name: name, help: help, kind: kind, labels: labels, series: make(map[string]*metricSeries)}
+}
+
+// with returns the series for labelValues, creating it if needed; the caller must hold v.mu
+func (v *metricVec) with(labelValues []string) *metricSeries {
+	key := strings.Join(labelValues, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &metricSeries{labelValues: labelValues}
+		if v.kind == "histogram" {
+			s.buckets = make([]uint64, len(defaultBuckets))
+		}
+		v.series[key] = s
+	}
+	return s
+}
+
+// Add adds delta to a counter
+func (v *metricVec) Add(delta float64, labelValues ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.with(labelValues).value += delta
+}
+
+// Inc adds one to a counter
+func (v *metricVec) Inc(labelValues ...string) {
+	v.Add(1, labelValues...)
+}
+
+// Set sets a gauge
+func (v *metricVec) Set(value float64, labelValues ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.with(labelValues).value = value
+}
+
+// Observe records a histogram observation
+func (v *metricVec) Observe(value float64, labelValues ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	s := v.with(labelValues)
+	s.value += value
+	s.count++
+	for i, bound := range defaultBuckets {
+		if value <= bound {
+			s.buckets[i]++
+		}
+	}
+}
+
+// ObserveDuration records the time since start in a histogram, in seconds
+func (v *metricVec) ObserveDuration(start time.Time, labelValues ...string) {
+	v.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// Value returns a counter or gauge's value, or a histogram's observation count
+func (v *metricVec) Value(labelValues ...string) float64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	s, ok := v.series[strings.Join(labelValues, "\xff")]
+	if !ok {
+		return 0
+	}
+	if v.kind == "histogram" {
+		return float64(s.count)
+	}
+	return s.value
+}
+
+// write writes the family's HELP and TYPE lines and each series, sorted by label values
+func (v *metricVec) write(w io.Writer) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", v.name, v.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", v.name, v.kind)
+
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := v.series[key]
+		if v.kind != "histogram" {
+			fmt.Fprintf(w, "%s%s %s\n", v.name, formatLabels(v.labels, s.labelValues), formatValue(s.value))
+			continue
+		}
+		bucketLabels := append(append([]string{}, v.labels...), "le")
+		for i, bound := range defaultBuckets {
+			bucketValues := append(append([]string{}, s.labelValues...), formatValue(bound))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, formatLabels(bucketLabels, bucketValues), s.buckets[i])
+		}
+		infValues := append(append([]string{}, s.labelValues...), "+Inf")
+		fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, formatLabels(bucketLabels, infValues), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", v.name, formatLabels(v.labels, s.labelValues), formatValue(s.value))
+		fmt.Fprintf(w, "%s_count%s %d\n", v.name, formatLabels(v.labels, s.labelValues), s.count)
+	}
+}
+
+// formatLabels formats label pairs like {code="200"}, or "" when there are none
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(values[i])
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, value)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue formats a sample value as Prometheus expects
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
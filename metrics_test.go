@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestMetricsExposition(t *testing.T) {
+	m := newMetrics()
+	m.FetchResponses.Inc("200")
+	m.FetchResponses.Add(2, "502")
+	m.ParseFailures.Inc()
+	m.JobLastSuccess.Set(1757899682, `feed "a"`)
+	m.RenderDuration.Observe(0.03, "atom")
+	m.RenderDuration.Observe(3, "atom")
+
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("ServeHTTP() Content-Type = %q, want text/plain; version=0.0.4", contentType)
+	}
+
+	wantLines := []string{
+		"# TYPE ghfeed_fetch_responses_total counter",
+		`ghfeed_fetch_responses_total{code="200"} 1`,
+		`ghfeed_fetch_responses_total{code="502"} 2`,
+		"ghfeed_parse_failures_total 1",
+		"# TYPE ghfeed_job_last_success_timestamp_seconds gauge",
+		`ghfeed_job_last_success_timestamp_seconds{job="feed \"a\""} 1.757899682e+09`,
+		"# TYPE ghfeed_render_duration_seconds histogram",
+		`ghfeed_render_duration_seconds_bucket{format="atom",le="0.025"} 0`,
+		`ghfeed_render_duration_seconds_bucket{format="atom",le="0.05"} 1`,
+		`ghfeed_render_duration_seconds_bucket{format="atom",le="5"} 2`,
+		`ghfeed_render_duration_seconds_bucket{format="atom",le="+Inf"} 2`,
+		`ghfeed_render_duration_seconds_sum{format="atom"} 3.03`,
+		`ghfeed_render_duration_seconds_count{format="atom"} 2`,
+		// Families with no series still describe themselves
+		"# TYPE ghfeed_commits_consolidated_total counter",
+	}
+	lines := strings.Split(body, "\n")
+	for _, want := range wantLines {
+		found := false
+		for _, line := range lines {
+			if line == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("ServeHTTP() output missing line %q", want)
+		}
+	}
+
+	// Series are sorted so output is stable between scrapes
+	if strings.Index(body, `code="200"`) > strings.Index(body, `code="502"`) {
+		t.Errorf("ServeHTTP() output isn't sorted by label:\n%s", body)
+	}
+}
+
+func TestFetchMetrics(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/garbage.atom" {
+			fmt.Fprint(w, "this is not a feed")
+			return
+		}
+		if attempts.Add(1) == 1 {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, privateFeedXML("http://"+r.Host))
+	}))
+	defer server.Close()
+
+	before502 := metrics.FetchResponses.Value("502")
+	before200 := metrics.FetchResponses.Value("200")
+	beforeLatency := metrics.FetchDuration.Value("200")
+	beforeParse := metrics.ParseFailures.Value()
+
+	if _, err := newTestFetcher().fetchFeed(server.URL + "/cdzombak.atom"); err != nil {
+		t.Fatalf("fetchFeed() error = %v", err)
+	}
+	if _, err := newTestFetcher().fetchFeed(server.URL + "/garbage.atom"); err == nil {
+		t.Fatal("fetchFeed() error = nil, want parse error")
+	}
+
+	if got := metrics.FetchResponses.Value("502") - before502; got != 1 {
+		t.Errorf("502 responses counted = %v, want 1", got)
+	}
+	if got := metrics.FetchResponses.Value("200") - before200; got != 2 {
+		t.Errorf("200 responses counted = %v, want 2", got)
+	}
+	if got := metrics.FetchDuration.Value("200") - beforeLatency; got != 2 {
+		t.Errorf("200 latency observations = %v, want 2", got)
+	}
+	if got := metrics.ParseFailures.Value() - beforeParse; got != 1 {
+		t.Errorf("parse failures counted = %v, want 1", got)
+	}
+}
+
+func TestActivityTypeMetrics(t *testing.T) {
+	beforeFork := metrics.ActivityItems.Value("fork")
+	beforeOther := metrics.ActivityItems.Value("other")
+
+	simplifyNonCommitItem(&gofeed.Item{Title: "cdzombak forked cdzombak/gofeed from mmcdole/gofeed"}, "cdzombak", "github.com")
+	simplifyNonCommitItem(&gofeed.Item{Title: "cdzombak starred mmcdole/gofeed"}, "cdzombak", "github.com")
+	simplifyNonCommitItem(&gofeed.Item{Title: "cdzombak made mmcdole/gofeed public"}, "cdzombak", "github.com")
+
+	if got := metrics.ActivityItems.Value("fork") - beforeFork; got != 1 {
+		t.Errorf("fork items counted = %v, want 1", got)
+	}
+	if got := metrics.ActivityItems.Value("other") - beforeOther; got != 2 {
+		t.Errorf("other items counted = %v, want 2", got)
+	}
+}
+
+func TestRenderMetrics(t *testing.T) {
+	before := metrics.RenderDuration.Value("json")
+
+	if err := renderFeed(io.Discard, &gofeed.Feed{Title: "test"}, "json"); err != nil {
+		t.Fatalf("renderFeed() error = %v", err)
+	}
+
+	if got := metrics.RenderDuration.Value("json") - before; got != 1 {
+		t.Errorf("json render observations = %v, want 1", got)
+	}
+}
+
+func TestActivityTypeString(t *testing.T) {
+	tests := []struct {
+		activityType ActivityType
+		expected     string
+	}{
+		{ActivityPullRequest, "pull_request"},
+		{ActivityFork, "fork"},
+		{ActivityBranchCreate, "branch_create"},
+		{ActivityBranchDelete, "branch_delete"},
+		{ActivityTagDelete, "tag_delete"},
+		{ActivityOther, "other"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.activityType.String(); got != tt.expected {
+			t.Errorf("ActivityType(%d).String() = %q, want %q", int(tt.activityType), got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Ways to handle automated activity, such as dependency updates
+const (
+	noiseKeep     = "keep"
+	noiseDrop     = "drop"
+	noiseCollapse = "collapse"
+)
+
+// noiseBranchPrefixes are branches created by dependency-update bots
+var noiseBranchPrefixes = []string{"dependabot/", "renovate/"}
+
+// filterNoise removes automated activity (pushes to dependency-update branches, activity by [bot] accounts,
+// and pushes whose commit messages all match opts.NoisePatterns) according to opts.Noise.
+// When collapsing, each repository's automated activity is replaced with a single summary item.
+func filterNoise(activities []Activity, username, host string, opts Options) []Activity {
+	if opts.Noise == "" || opts.Noise == noiseKeep {
+		return activities
+	}
+
+	kept := []Activity{}
+	noiseByRepo := make(map[string][]Activity)
+	for _, activity := range activities {
+		if !isNoise(activity, opts.NoisePatterns) {
+			kept = append(kept, activity)
+			continue
+		}
+		logger.Debug("found automated activity", append(activityAttrs(activity, username), "noise", opts.Noise)...)
+		repo := activityRepo(activity, username)
+		noiseByRepo[repo] = append(noiseByRepo[repo], activity)
+	}
+
+	if opts.Noise == noiseCollapse {
+		for repo, noise := range noiseByRepo {
+			var sources []*gofeed.Item
+			for _, activity := range noise {
+				sources = append(sources, activitySources(activity)...)
+			}
+			kept = append(kept, Activity{Item: createAutomatedUpdatesItem(repo, noise, host), Sources: sources})
+		}
+	}
+
+	return kept
+}
+
+// isNoise determines whether an activity was made by automation rather than a person
+func isNoise(activity Activity, patterns []*regexp.Regexp) bool {
+	if push := activity.Push; push != nil {
+		for _, prefix := range noiseBranchPrefixes {
+			if strings.HasPrefix(push.Branch, prefix) {
+				return true
+			}
+		}
+		if isBotName(push.Actor) {
+			return true
+		}
+		if len(patterns) == 0 || len(push.Commits) == 0 {
+			return false
+		}
+		for _, commit := range push.Commits {
+			if !matchesAny(commit.Message, patterns) {
+				return false
+			}
+		}
+		return true
+	}
+
+	item := activity.Item
+	for _, prefix := range noiseBranchPrefixes {
+		if strings.Contains(item.Title, " "+prefix) {
+			return true
+		}
+	}
+	if isBotName(itemAuthor(item)) {
+		return true
+	}
+	actor, _, _ := strings.Cut(item.Title, " ")
+	return isBotName(actor)
+}
+
+// isBotName determines whether a user name belongs to a bot account (e.g., dependabot[bot])
+func isBotName(name string) bool {
+	return strings.HasSuffix(name, "[bot]")
+}
+
+// matchesAny determines whether s matches any of the patterns
+func matchesAny(s string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// activityRepo returns the repository an activity belongs to, as owner/repo when known
+func activityRepo(activity Activity, username string) string {
+	if push := activity.Push; push != nil {
+		owner := push.Owner
+		if owner == "" {
+			owner = username
+		}
+		return owner + "/" + push.Repo
+	}
+
+	repoRegex := regexp.MustCompile(`^https?://[^/]+/([^/]+/[^/#?]+)`)
+	if matches := repoRegex.FindStringSubmatch(activity.Item.Link); matches != nil {
+		return matches[1]
+	}
+	return ""
+}
+
+// activityTime returns when an activity happened, or the zero time if it's unknown
+func activityTime(activity Activity) time.Time {
+	if activity.Push != nil {
+		return pushTime(activity.Push)
+	}
+	if activity.Item.UpdatedParsed != nil {
+		return *activity.Item.UpdatedParsed
+	}
+	if activity.Item.PublishedParsed != nil {
+		return *activity.Item.PublishedParsed
+	}
+	return time.Time{}
+}
+
+// createAutomatedUpdatesItem creates a single item summarizing a repository's automated activity
+func createAutomatedUpdatesItem(repo string, noise []Activity, host string) *gofeed.Item {
+	// List the most recent activity first
+	sort.SliceStable(noise, func(i, j int) bool {
+		return activityTime(noise[i]).After(activityTime(noise[j]))
+	})
+	latest := activityTime(noise[0])
+
+	updateWord := "updates"
+	if len(noise) == 1 {
+		updateWord = "update"
+	}
+	title := fmt.Sprintf("%d automated %s", len(noise), updateWord)
+	link := ""
+	if repo != "" {
+		title += fmt.Sprintf(" in %s", repo)
+		if host != "" && strings.Contains(repo, "/") {
+			link = fmt.Sprintf("%s/%s", hostURL(host), repo)
+		}
+	}
+
+	var htmlParts []string
+	htmlParts = append(htmlParts, "<div>")
+	for _, activity := range noise {
+		if push := activity.Push; push != nil {
+			message := ""
+			if len(push.Commits) > 0 {
+				message = push.Commits[0].Message
+			}
+			htmlParts = append(htmlParts, fmt.Sprintf(
+				"<div style='margin-bottom: 12px;'><a href='%s'>%s</a>: %s</div>",
+				push.CompareLink,
+				html.EscapeString(push.Branch),
+				message,
+			))
+			if link == "" {
+				link = push.CompareLink
+			}
+			continue
+		}
+		htmlParts = append(htmlParts, fmt.Sprintf(
+			"<div style='margin-bottom: 12px;'><a href='%s'>%s</a></div>",
+			activity.Item.Link,
+			html.EscapeString(activity.Item.Title),
+		))
+		if link == "" {
+			link = activity.Item.Link
+		}
+	}
+	htmlParts = append(htmlParts, "</div>")
+	htmlContent := strings.Join(htmlParts, "")
+
+	return &gofeed.Item{
+		Title:           title,
+		Description:     htmlContent,
+		Content:         htmlContent,
+		Link:            link,
+		Published:       latest.Format(time.RFC3339),
+		PublishedParsed: &latest,
+		Updated:         latest.Format(time.RFC3339),
+		UpdatedParsed:   &latest,
+		GUID:            fmt.Sprintf("automated-%s-%d", repo, latest.Unix()),
+	}
+}
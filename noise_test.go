@@ -0,0 +1,121 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// noiseTestActivities returns one human push followed by automated activity in cdzombak/ghfeed
+func noiseTestActivities() []Activity {
+	at := func(minute int) *time.Time {
+		t := time.Date(2025, 9, 15, 1, minute, 0, 0, time.UTC)
+		return &t
+	}
+	push := func(actor, branch, message string, minute int) Activity {
+		return Activity{Push: &BranchActivity{
+			Actor:        actor,
+			Repo:         "ghfeed",
+			Branch:       branch,
+			Commits:      []Commit{{Hash: "b19a1b6", Message: message, Link: "https://github.com/cdzombak/ghfeed/commit/b19a1b604e"}},
+			LatestTime:   at(minute),
+			CompareLink:  "https://github.com/cdzombak/ghfeed/commit/b19a1b604e",
+			TotalCommits: 1,
+		}}
+	}
+
+	return []Activity{
+		push("", "main", "Fix build", 50),
+		push("", "dependabot/go_modules/golang.org/x/net-0.38.0", "Bump golang.org/x/net from 0.37.0 to 0.38.0", 40),
+		push("renovate[bot]", "main", "Update module github.com/mmcdole/gofeed to v1.3.1", 30),
+		push("", "main", "chore(deps): update actions/checkout", 20),
+		{Item: &gofeed.Item{
+			Title:           "cdzombak created branch renovate/actions in cdzombak/ghfeed",
+			Link:            "https://github.com/cdzombak/ghfeed/tree/renovate/actions",
+			PublishedParsed: at(10),
+		}},
+	}
+}
+
+func TestFilterNoise(t *testing.T) {
+	patterns := []*regexp.Regexp{regexp.MustCompile(`^chore\(deps\)`)}
+
+	kept := filterNoise(noiseTestActivities(), "cdzombak", "github.com", Options{Noise: noiseKeep, NoisePatterns: patterns})
+	if len(kept) != 5 {
+		t.Errorf("filterNoise(keep) kept %d activities, want 5", len(kept))
+	}
+
+	dropped := filterNoise(noiseTestActivities(), "cdzombak", "github.com", Options{Noise: noiseDrop, NoisePatterns: patterns})
+	if len(dropped) != 1 || dropped[0].Push == nil || dropped[0].Push.Commits[0].Message != "Fix build" {
+		t.Errorf("filterNoise(drop) = %+v, want only the human push", dropped)
+	}
+
+	// Without patterns, the chore(deps) push is a person's
+	unpatterned := filterNoise(noiseTestActivities(), "cdzombak", "github.com", Options{Noise: noiseDrop})
+	if len(unpatterned) != 2 {
+		t.Errorf("filterNoise(drop) without patterns kept %d activities, want 2", len(unpatterned))
+	}
+
+	collapsed := filterNoise(noiseTestActivities(), "cdzombak", "github.com", Options{Noise: noiseCollapse, NoisePatterns: patterns})
+	if len(collapsed) != 2 {
+		t.Fatalf("filterNoise(collapse) = %d activities, want 2", len(collapsed))
+	}
+	summary := collapsed[1].Item
+	if summary == nil {
+		t.Fatalf("filterNoise(collapse) second activity isn't an item")
+	}
+	if summary.Title != "4 automated updates in cdzombak/ghfeed" {
+		t.Errorf("automated updates title = %v", summary.Title)
+	}
+	if summary.Link != "https://github.com/cdzombak/ghfeed" {
+		t.Errorf("automated updates link = %v", summary.Link)
+	}
+	if summary.UpdatedParsed == nil || summary.UpdatedParsed.Minute() != 40 {
+		t.Errorf("automated updates time = %v, want the latest automated activity", summary.UpdatedParsed)
+	}
+	if !strings.Contains(summary.Content, "Bump golang.org/x/net from 0.37.0 to 0.38.0") {
+		t.Errorf("automated updates content missing dependabot push, got %v", summary.Content)
+	}
+}
+
+func TestCreateAutomatedUpdatesItemEscapesText(t *testing.T) {
+	published := time.Date(2025, 9, 15, 1, 0, 0, 0, time.UTC)
+	noise := []Activity{
+		{Push: &BranchActivity{
+			Repo:       "ghfeed",
+			Branch:     "renovate/<b>&co",
+			Commits:    []Commit{{Hash: "b19a1b6", Message: "Update deps"}},
+			LatestTime: &published,
+		}},
+		{Item: &gofeed.Item{
+			Title:           "renovate[bot] opened issue #1: Dependency <dashboard> & more",
+			Link:            "https://github.com/cdzombak/ghfeed/issues/1",
+			PublishedParsed: &published,
+		}},
+	}
+
+	item := createAutomatedUpdatesItem("cdzombak/ghfeed", noise, "github.com")
+	for _, want := range []string{"renovate/&lt;b&gt;&amp;co", "Dependency &lt;dashboard&gt; &amp; more"} {
+		if !strings.Contains(item.Content, want) {
+			t.Errorf("automated updates content = %v, want it to contain %v", item.Content, want)
+		}
+	}
+}
+
+func TestIsBotName(t *testing.T) {
+	tests := map[string]bool{
+		"dependabot[bot]": true,
+		"renovate[bot]":   true,
+		"cdzombak":        false,
+		"":                false,
+	}
+
+	for name, expected := range tests {
+		if result := isBotName(name); result != expected {
+			t.Errorf("isBotName(%q) = %v, want %v", name, result, expected)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// detectOrgFeed returns the organization an Atom feed is for, from its links, or "" for other feeds.
+// Organization feeds are github.com/orgs/org.atom and the organization dashboard feed,
+// github.com/organizations/org/username.private.atom.
+func detectOrgFeed(feed *gofeed.Feed, host string) string {
+	orgRegex := regexp.MustCompile(`^/(?:orgs/([^/.]+)(?:\.atom)?|organizations/([^/]+)(?:/[^/]*)?)/?$`)
+
+	for _, link := range []string{feed.Link, feed.FeedLink} {
+		parsed, err := url.Parse(link)
+		if err != nil || !strings.EqualFold(parsed.Host, host) {
+			continue
+		}
+
+		if matches := orgRegex.FindStringSubmatch(parsed.Path); matches != nil {
+			if matches[1] != "" {
+				return matches[1]
+			}
+			return matches[2]
+		}
+	}
+
+	return ""
+}
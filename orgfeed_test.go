@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestDetectOrgFeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		feedLink string
+		expected string
+	}{
+		{"Org feed", "https://github.com/orgs/ourorg", "https://github.com/orgs/ourorg.atom", "ourorg"},
+		{"Org dashboard feed", "https://github.com/organizations/ourorg/cdzombak", "https://github.com/organizations/ourorg/cdzombak.private.atom?token=x", "ourorg"},
+		{"Org dashboard feed link only", "", "https://github.com/organizations/ourorg/cdzombak.private.atom", "ourorg"},
+		{"User feed", "https://github.com/cdzombak", "https://github.com/cdzombak.atom", ""},
+		{"Org's repository", "https://github.com/ourorg/orgs", "", ""},
+		{"Other host", "https://github.example.com/orgs/ourorg", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := &gofeed.Feed{Link: tt.link, FeedLink: tt.feedLink}
+			if result := detectOrgFeed(feed, "github.com"); result != tt.expected {
+				t.Errorf("detectOrgFeed() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConsolidateCommitsOrgFeed(t *testing.T) {
+	at := func(hour int) *time.Time {
+		t := time.Date(2025, 9, 15, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	push := func(actor, repo, hash string, hour int) *gofeed.Item {
+		return &gofeed.Item{
+			Title:           actor + " pushed " + repo,
+			Link:            "https://github.com/" + repo + "/compare/a0a0a0a0a0..." + hash,
+			Content:         `<a class="branch-name" href="/` + repo + `/tree/main">main</a><code><a href="/` + repo + `/commit/` + hash + `">` + hash[:7] + `</a></code><div><blockquote>Update</blockquote></div>`,
+			Authors:         []*gofeed.Person{{Name: actor}},
+			PublishedParsed: at(hour),
+		}
+	}
+
+	feed := &gofeed.Feed{
+		Link:     "https://github.com/organizations/ourorg/cdzombak",
+		FeedLink: "https://github.com/organizations/ourorg/cdzombak.private.atom",
+		Items: []*gofeed.Item{
+			push("alice", "ourorg/service", "5555555555", 5),
+			push("bob", "ourorg/service", "4444444444", 4),
+			push("alice", "ourorg/service", "3333333333", 3),
+			push("bob", "mmcdole/gofeed", "2222222222", 2),
+			{
+				Title:           "alice opened a pull request in ourorg/web",
+				Link:            "https://github.com/ourorg/web/pull/7",
+				Authors:         []*gofeed.Person{{Name: "alice"}},
+				PublishedParsed: at(1),
+			},
+		},
+	}
+	result := consolidateCommits(feed, Options{ConsolidatePushes: true})
+
+	expected := []struct {
+		title string
+		link  string
+	}{
+		{"alice pushed 2 commits to service/main", "https://github.com/ourorg/service/compare/3333333333^...5555555555"},
+		{"bob pushed 1 commit to service/main", "https://github.com/ourorg/service/commit/4444444444"},
+		{"bob pushed 1 commit to mmcdole/gofeed/main", "https://github.com/mmcdole/gofeed/commit/2222222222"},
+		{"alice opened PR #7 in ourorg/web", "https://github.com/ourorg/web/pull/7"},
+	}
+	if len(result.Items) != len(expected) {
+		t.Fatalf("consolidateCommits() items count = %d, want %d", len(result.Items), len(expected))
+	}
+	for i, want := range expected {
+		if result.Items[i].Title != want.title {
+			t.Errorf("consolidateCommits() item %d title = %v, want %v", i, result.Items[i].Title, want.title)
+		}
+		if !strings.HasPrefix(result.Items[i].Link, want.link) {
+			t.Errorf("consolidateCommits() item %d link = %v, want %v", i, result.Items[i].Link, want.link)
+		}
+	}
+}
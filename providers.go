@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Supported forges whose activity feeds can be consolidated
+const (
+	providerGitHub = "github"
+	providerGitea  = "gitea"
+	providerGitLab = "gitlab"
+	// providerGit is set by the git source; local repositories have no compare view
+	providerGit = "git"
+)
+
+// normalizeProvider maps a -provider value to a supported provider, returning "" if it isn't supported.
+// Forgejo is a Gitea fork with the same feed format.
+func normalizeProvider(name string) string {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "github":
+		return providerGitHub
+	case "gitea", "forgejo":
+		return providerGitea
+	case "gitlab":
+		return providerGitLab
+	default:
+		return ""
+	}
+}
+
+// compareURL returns the provider's web URL comparing base...head in a repository, or "" if it has none
+func compareURL(provider, host, owner, repo, base, head string) string {
+	if provider == providerGit {
+		return ""
+	}
+	repoURL := fmt.Sprintf("%s/%s/%s", hostURL(host), owner, repo)
+	if provider == providerGitLab {
+		return fmt.Sprintf("%s/-/compare/%s...%s", repoURL, base, head)
+	}
+	return fmt.Sprintf("%s/compare/%s...%s", repoURL, base, head)
+}
+
+// htmlText reduces an HTML fragment to its plain text, collapsing whitespace
+func htmlText(fragment string) string {
+	tagRegex := regexp.MustCompile(`<[^>]*>`)
+	text := html.UnescapeString(tagRegex.ReplaceAllString(fragment, " "))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// firstLine returns the first non-empty line of text, trimmed
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// absoluteLink makes a host-relative link (e.g., /owner/repo/commit/abc) absolute
+func absoluteLink(link, host string) string {
+	if strings.HasPrefix(link, "/") {
+		return hostURL(host) + link
+	}
+	return link
+}
+
+// itemBody returns an item's content, falling back to its description (RSS feeds only have the latter)
+func itemBody(item *gofeed.Item) string {
+	if item.Content != "" {
+		return item.Content
+	}
+	return item.Description
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// defaultCommitWindow groups a commits feed's entries into pushes when -push-window isn't given
+const defaultCommitWindow = time.Hour
+
+// FeedKind identifies what kind of GitHub Atom feed is being processed
+type FeedKind int
+
+const (
+	// FeedKindUser is a user's activity feed (e.g., github.com/username.atom)
+	FeedKindUser FeedKind = iota
+	// FeedKindCommits is a repository branch's commits feed (e.g., github.com/owner/repo/commits/main.atom)
+	FeedKindCommits
+	// FeedKindReleases is a repository's releases feed (e.g., github.com/owner/repo/releases.atom)
+	FeedKindReleases
+)
+
+// RepoFeed describes a per-repository feed
+type RepoFeed struct {
+	Kind   FeedKind
+	Owner  string
+	Repo   string
+	Branch string
+}
+
+// detectRepoFeed determines whether the feed is a per-repository commits or releases feed from its links,
+// returning nil for user activity feeds
+func detectRepoFeed(feed *gofeed.Feed, host string) *RepoFeed {
+	commitsRegex := regexp.MustCompile(`^/([^/]+)/([^/]+)/commits(?:/(.+?))?(?:\.atom)?/?$`)
+	releasesRegex := regexp.MustCompile(`^/([^/]+)/([^/]+)/releases(?:\.atom)?/?$`)
+
+	for _, link := range []string{feed.Link, feed.FeedLink} {
+		parsed, err := url.Parse(link)
+		if err != nil || !strings.EqualFold(parsed.Host, host) {
+			continue
+		}
+
+		if matches := commitsRegex.FindStringSubmatch(parsed.Path); matches != nil {
+			branch := matches[3]
+			if branch == "" {
+				branch = "master" // default, as in extractBranchActivity
+			}
+			return &RepoFeed{Kind: FeedKindCommits, Owner: matches[1], Repo: matches[2], Branch: branch}
+		}
+		if matches := releasesRegex.FindStringSubmatch(parsed.Path); matches != nil {
+			return &RepoFeed{Kind: FeedKindReleases, Owner: matches[1], Repo: matches[2]}
+		}
+	}
+
+	return nil
+}
+
+// extractCommitFeedActivities turns each entry of a commits feed into a single-commit push by its author,
+// to be grouped into push-style items by consolidateActivities
+func extractCommitFeedActivities(feed *gofeed.Feed, repoFeed *RepoFeed, host string) []Activity {
+	activities := []Activity{}
+	for _, item := range feed.Items {
+		hash := extractCommitHashFromLink(item.Link)
+		if hash == "" {
+			activities = append(activities, Activity{Item: simplifyOtherActivity(item, repoFeed.Owner), Sources: []*gofeed.Item{item}})
+			continue
+		}
+
+		latestTime := item.UpdatedParsed
+		if latestTime == nil {
+			latestTime = item.PublishedParsed
+		}
+
+		activities = append(activities, Activity{Push: &BranchActivity{
+			Actor:  itemAuthor(item),
+			Owner:  repoFeed.Owner,
+			Repo:   repoFeed.Repo,
+			Branch: repoFeed.Branch,
+			Commits: []Commit{
+				{
+					Hash:    shortHash(hash, 7),
+					Message: html.EscapeString(strings.TrimSpace(item.Title)),
+					Link:    item.Link,
+				},
+			},
+			LatestTime:   latestTime,
+			CompareLink:  item.Link,
+			TotalCommits: 1,
+			Sources:      []*gofeed.Item{item},
+		}})
+	}
+	return activities
+}
+
+// extractReleaseFeedActivities creates a clean entry for each release in a releases feed
+func extractReleaseFeedActivities(feed *gofeed.Feed, repoFeed *RepoFeed) []Activity {
+	activities := []Activity{}
+	for _, item := range feed.Items {
+		activities = append(activities, Activity{Item: simplifyRelease(item, repoFeed), Sources: []*gofeed.Item{item}})
+	}
+	return activities
+}
+
+// simplifyRelease creates a clean release entry, keeping the release notes
+func simplifyRelease(item *gofeed.Item, repoFeed *RepoFeed) *gofeed.Item {
+	tagName := ""
+	tagRegex := regexp.MustCompile(`/releases/tag/([^/?#]+)`)
+	if matches := tagRegex.FindStringSubmatch(item.Link); len(matches) > 1 {
+		tagName, _ = url.PathUnescape(matches[1])
+	}
+
+	// Titles and tag names are plain text; escape them like the HTML they're used with
+	releaseName := html.EscapeString(strings.TrimSpace(item.Title))
+	tagName = html.EscapeString(tagName)
+	if releaseName == "" {
+		releaseName = tagName
+	}
+
+	author := itemAuthor(item)
+	if author == "" {
+		author = repoFeed.Owner
+	}
+
+	title := fmt.Sprintf("%s released %s in %s/%s", author, releaseName, repoFeed.Owner, repoFeed.Repo)
+
+	htmlContent := `<div style='margin-bottom: 12px;'>`
+	if tagName != "" {
+		htmlContent += fmt.Sprintf(`<a href='%s'>View release <tt>%s</tt></a>`, item.Link, tagName)
+	} else {
+		htmlContent += fmt.Sprintf(`<a href='%s'>View release</a>`, item.Link)
+	}
+	htmlContent += `</div>`
+	if notes := strings.TrimSpace(item.Content); notes != "" && notes != "<p>No content.</p>" {
+		htmlContent += `<div style='margin-top: 8px;'>` + notes + `</div>`
+	}
+
+	return &gofeed.Item{
+		Title:           title,
+		Description:     htmlContent,
+		Content:         htmlContent,
+		Link:            item.Link,
+		Published:       item.Published,
+		PublishedParsed: item.PublishedParsed,
+		Updated:         item.Updated,
+		UpdatedParsed:   item.UpdatedParsed,
+		Authors:         item.Authors,
+		GUID:            item.GUID,
+	}
+}
+
+// itemAuthor returns the name of an item's first author, if any
+func itemAuthor(item *gofeed.Item) string {
+	if item.Author != nil && item.Author.Name != "" {
+		return item.Author.Name
+	}
+	for _, author := range item.Authors {
+		if author != nil && author.Name != "" {
+			return author.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// parseTestFeed parses an Atom fixture from testdata
+func parseTestFeed(t *testing.T, name string) *gofeed.Feed {
+	t.Helper()
+
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	feed, err := gofeed.NewParser().Parse(f)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", name, err)
+	}
+	return feed
+}
+
+func TestDetectRepoFeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		feed     *gofeed.Feed
+		expected *RepoFeed
+	}{
+		{
+			name:     "User feed",
+			feed:     &gofeed.Feed{Link: "https://github.com/cdzombak"},
+			expected: nil,
+		},
+		{
+			name:     "Commits feed",
+			feed:     &gofeed.Feed{Link: "https://github.com/cdzombak/ghfeed/commits/main"},
+			expected: &RepoFeed{Kind: FeedKindCommits, Owner: "cdzombak", Repo: "ghfeed", Branch: "main"},
+		},
+		{
+			name:     "Commits feed for branch with slashes",
+			feed:     &gofeed.Feed{FeedLink: "https://github.com/cdzombak/ghfeed/commits/cdz/feature.atom"},
+			expected: &RepoFeed{Kind: FeedKindCommits, Owner: "cdzombak", Repo: "ghfeed", Branch: "cdz/feature"},
+		},
+		{
+			name:     "Releases feed",
+			feed:     &gofeed.Feed{Link: "https://github.com/cdzombak/ghfeed/releases"},
+			expected: &RepoFeed{Kind: FeedKindReleases, Owner: "cdzombak", Repo: "ghfeed"},
+		},
+		{
+			name:     "Other host",
+			feed:     &gofeed.Feed{Link: "https://example.com/cdzombak/ghfeed/releases"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detectRepoFeed(tt.feed, "github.com")
+			if tt.expected == nil {
+				if result != nil {
+					t.Errorf("detectRepoFeed() = %+v, want nil", result)
+				}
+				return
+			}
+			if result == nil || *result != *tt.expected {
+				t.Errorf("detectRepoFeed() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConsolidateCommitsFeed(t *testing.T) {
+	feed := parseTestFeed(t, "commits.atom")
+
+	result := consolidateCommits(feed, Options{ConsolidatePushes: true})
+
+	// octocat's commit stands alone; cdzombak's two recent commits are grouped,
+	// and the much older initial commit falls outside the default window
+	expectedTitles := []string{
+		"octocat pushed 1 commit to ghfeed/main",
+		"cdzombak pushed 2 commits to ghfeed/main",
+		"cdzombak pushed 1 commit to ghfeed/main",
+	}
+	if len(result.Items) != len(expectedTitles) {
+		t.Fatalf("consolidateCommits() items count = %d, want %d", len(result.Items), len(expectedTitles))
+	}
+	for i, expected := range expectedTitles {
+		if result.Items[i].Title != expected {
+			t.Errorf("consolidateCommits().Items[%d].Title = %v, want %v", i, result.Items[i].Title, expected)
+		}
+	}
+
+	grouped := result.Items[1]
+	expectedLink := "https://github.com/cdzombak/ghfeed/compare/c0ffee0000000000000000000000000000000002^...c0ffee0000000000000000000000000000000003"
+	if grouped.Link != expectedLink {
+		t.Errorf("grouped commits link = %v, want %v", grouped.Link, expectedLink)
+	}
+	if !strings.Contains(grouped.Content, "add commits feed support") || !strings.Contains(grouped.Content, "detect feed kind") {
+		t.Errorf("grouped commits content missing messages, got %v", grouped.Content)
+	}
+	if strings.Index(grouped.Content, "add commits feed support") > strings.Index(grouped.Content, "detect feed kind") {
+		t.Errorf("grouped commits should be listed newest first, got %v", grouped.Content)
+	}
+
+	// A wider window pulls the initial commit into the same group
+	result = consolidateCommits(feed, Options{ConsolidatePushes: true, PushWindow: 12 * time.Hour})
+	if len(result.Items) != 2 {
+		t.Fatalf("consolidateCommits(12h window) items count = %d, want 2", len(result.Items))
+	}
+	if result.Items[1].Title != "cdzombak pushed 3 commits to ghfeed/main" {
+		t.Errorf("consolidateCommits(12h window).Items[1].Title = %v", result.Items[1].Title)
+	}
+
+	// Without consolidation, each commit is its own item
+	result = consolidateCommits(feed, Options{ConsolidatePushes: false})
+	if len(result.Items) != 4 {
+		t.Errorf("consolidateCommits(consolidate=false) items count = %d, want 4", len(result.Items))
+	}
+}
+
+func TestConsolidateReleasesFeed(t *testing.T) {
+	feed := parseTestFeed(t, "releases.atom")
+
+	result := consolidateCommits(feed, Options{ConsolidatePushes: true})
+	if len(result.Items) != 2 {
+		t.Fatalf("consolidateCommits() items count = %d, want 2", len(result.Items))
+	}
+
+	latest := result.Items[0]
+	if latest.Title != "cdzombak released v1.2.0 in cdzombak/ghfeed" {
+		t.Errorf("release title = %v", latest.Title)
+	}
+	if latest.Link != "https://github.com/cdzombak/ghfeed/releases/tag/v1.2.0" {
+		t.Errorf("release link = %v", latest.Link)
+	}
+	if !strings.Contains(latest.Content, "View release <tt>v1.2.0</tt>") || !strings.Contains(latest.Content, "Add commits feed support") {
+		t.Errorf("release content = %v", latest.Content)
+	}
+
+	if strings.Contains(result.Items[1].Content, "No content.") {
+		t.Errorf("empty release notes placeholder should be dropped, got %v", result.Items[1].Content)
+	}
+}
+
+func TestRepoFeedsEscapeText(t *testing.T) {
+	published := time.Date(2025, 9, 15, 1, 0, 0, 0, time.UTC)
+	repoFeed := &RepoFeed{Kind: FeedKindCommits, Owner: "cdzombak", Repo: "ghfeed", Branch: "main"}
+
+	commits := extractCommitFeedActivities(&gofeed.Feed{Items: []*gofeed.Item{{
+		Title:           "Use <fzf> & fd",
+		Link:            "https://github.com/cdzombak/ghfeed/commit/c0ffee0000000000000000000000000000000001",
+		PublishedParsed: &published,
+	}}}, repoFeed, "github.com")
+	if message := commits[0].Push.Commits[0].Message; message != "Use &lt;fzf&gt; &amp; fd" {
+		t.Errorf("commit message = %q, want it escaped as HTML", message)
+	}
+
+	release := simplifyRelease(&gofeed.Item{
+		Title: "v2 <beta> & more",
+		Link:  "https://github.com/cdzombak/ghfeed/releases/tag/v2%3Cbeta%3E",
+	}, repoFeed)
+	if release.Title != "cdzombak released v2 &lt;beta&gt; &amp; more in cdzombak/ghfeed" {
+		t.Errorf("release title = %q, want the release name escaped", release.Title)
+	}
+	if !strings.Contains(release.Content, "<tt>v2&lt;beta&gt;</tt>") {
+		t.Errorf("release content = %v, want the tag name escaped", release.Content)
+	}
+}
+
+func TestMergePushesWindow(t *testing.T) {
+	t1 := time.Date(2025, 9, 15, 1, 0, 0, 0, time.UTC)
+	t2 := t1.Add(30 * time.Minute)
+	t3 := t1.Add(5 * time.Hour)
+
+	newPushes := func() []*BranchActivity {
+		return []*BranchActivity{
+			{Repo: "dotfiles", Branch: "master", Commits: []Commit{{Hash: "aaa"}}, LatestTime: &t3, TotalCommits: 1},
+			{Repo: "dotfiles", Branch: "master", Commits: []Commit{{Hash: "bbb"}}, LatestTime: &t1, TotalCommits: 1},
+			{Repo: "dotfiles", Branch: "master", Commits: []Commit{{Hash: "ccc"}}, LatestTime: &t2, TotalCommits: 1},
+		}
+	}
+
+	if groups := mergePushes(newPushes(), 0); len(groups) != 1 || len(groups[0].Commits) != 3 {
+		t.Errorf("mergePushes(no window) = %d groups, want 1 with 3 commits", len(groups))
+	}
+
+	groups := mergePushes(newPushes(), time.Hour)
+	if len(groups) != 2 {
+		t.Fatalf("mergePushes(1h) = %d groups, want 2", len(groups))
+	}
+	if len(groups[0].Commits) != 1 || groups[0].Commits[0].Hash != "aaa" {
+		t.Errorf("mergePushes(1h)[0] = %+v, want only the newest push", groups[0].Commits)
+	}
+	if len(groups[1].Commits) != 2 || groups[1].Commits[0].Hash != "ccc" || groups[1].Commits[1].Hash != "bbb" {
+		t.Errorf("mergePushes(1h)[1] = %+v, want ccc then bbb", groups[1].Commits)
+	}
+	if !groups[1].LatestTime.Equal(t2) {
+		t.Errorf("mergePushes(1h)[1].LatestTime = %v, want %v", groups[1].LatestTime, t2)
+	}
+}
+
+func TestMergePushesTruncated(t *testing.T) {
+	t1 := time.Date(2025, 9, 15, 1, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	t3 := t1.Add(2 * time.Hour)
+	commit := func(hash string) Commit {
+		return Commit{Hash: hash[:7], Link: "https://github.com/cdzombak/ghfeed/commit/" + hash}
+	}
+
+	pushes := []*BranchActivity{
+		{
+			Repo: "ghfeed", Branch: "master", LatestTime: &t3,
+			Commits:     []Commit{commit("ccccccccc")},
+			CompareLink: "https://github.com/cdzombak/ghfeed/compare/bbbbbbbbb...ccccccccc", TotalCommits: 1,
+		},
+		{
+			Repo: "ghfeed", Branch: "master", LatestTime: &t2,
+			Commits:         []Commit{commit("bbbbbbbbb"), commit("bbbbbbbb8")},
+			CompareLink:     "https://github.com/cdzombak/ghfeed/compare/aaaaaaaaa...bbbbbbbbb",
+			TotalCommits:    30,
+			MoreCommitsLink: "https://github.com/cdzombak/ghfeed/compare/aaaaaaaaa...bbbbbbbbb",
+		},
+		{
+			Repo: "ghfeed", Branch: "master", LatestTime: &t1,
+			Commits:         []Commit{commit("aaaaaaaaa"), commit("aaaaaaaa8")},
+			CompareLink:     "https://github.com/cdzombak/ghfeed/compare/000000000...aaaaaaaaa",
+			TotalCommits:    12,
+			MoreCommitsLink: "https://github.com/cdzombak/ghfeed/compare/000000000...aaaaaaaaa",
+		},
+	}
+
+	groups := mergePushes(pushes, 0)
+	if len(groups) != 1 {
+		t.Fatalf("mergePushes() = %d groups, want 1", len(groups))
+	}
+	if hidden := commitCount(groups[0]) - len(groups[0].Commits); hidden != 38 {
+		t.Errorf("mergePushes() hides %d commits, want 38", hidden)
+	}
+	want := "https://github.com/cdzombak/ghfeed/compare/000000000...ccccccccc"
+	if groups[0].MoreCommitsLink != want {
+		t.Errorf("mergePushes().MoreCommitsLink = %v, want %v", groups[0].MoreCommitsLink, want)
+	}
+	if link := generateComparisonLink(groups[0], "cdzombak", "github.com", providerGitHub); link != want {
+		t.Errorf("generateComparisonLink() = %v, want %v", link, want)
+	}
+}
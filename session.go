@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// groupSessions groups consolidated pushes into sessions: each actor's pushes less than window apart.
+// Sessions touching a single repository are split back into their pushes, since those already have
+// their own items. With no window, every push is its own session.
+func groupSessions(pushes []*BranchActivity, window time.Duration, username string) [][]*BranchActivity {
+	var sessions [][]*BranchActivity
+	if window <= 0 {
+		for _, push := range pushes {
+			sessions = append(sessions, []*BranchActivity{push})
+		}
+		return sessions
+	}
+
+	byActor := make(map[string][]*BranchActivity)
+	for _, push := range pushes {
+		byActor[push.Actor] = append(byActor[push.Actor], push)
+	}
+
+	for _, actorPushes := range byActor {
+		// Work newest-first so sessions list pushes newest-first
+		sort.SliceStable(actorPushes, func(i, j int) bool {
+			return pushTime(actorPushes[i]).After(pushTime(actorPushes[j]))
+		})
+
+		var current []*BranchActivity
+		for _, push := range actorPushes {
+			if len(current) > 0 && pushTime(current[len(current)-1]).Sub(pushTime(push)) > window {
+				sessions = append(sessions, splitSingleRepoSession(current, username)...)
+				current = nil
+			}
+			current = append(current, push)
+		}
+		sessions = append(sessions, splitSingleRepoSession(current, username)...)
+	}
+	return sessions
+}
+
+// splitSingleRepoSession returns a session touching several repositories as is, and otherwise each of
+// its pushes as a session of its own
+func splitSingleRepoSession(session []*BranchActivity, username string) [][]*BranchActivity {
+	if pushesRepo(session, username) == "" {
+		return [][]*BranchActivity{session}
+	}
+
+	var sessions [][]*BranchActivity
+	for _, push := range session {
+		sessions = append(sessions, []*BranchActivity{push})
+	}
+	return sessions
+}
+
+// pushesRepo returns the owner/repo all the pushes are to, or "" if they're to several repositories
+func pushesRepo(pushes []*BranchActivity, username string) string {
+	var repo string
+	for i, push := range pushes {
+		pushRepo := activityRepo(Activity{Push: push}, username)
+		if i > 0 && pushRepo != repo {
+			return ""
+		}
+		repo = pushRepo
+	}
+	return repo
+}
+
+// createSessionItem creates a single item for a session of pushes across repositories, with a section
+// for each repository/branch
+func createSessionItem(session []*BranchActivity, username string, conventional bool) *gofeed.Item {
+	count := 0
+	var allCommits []Commit
+	repos := make(map[string]bool)
+	var earliest, latest time.Time
+	for _, push := range session {
+		count += commitCount(push)
+		allCommits = append(allCommits, push.Commits...)
+		repos[activityRepo(Activity{Push: push}, username)] = true
+		if t := pushTime(push); earliest.IsZero() || t.Before(earliest) {
+			earliest = t
+		}
+		if t := pushTime(push); t.After(latest) {
+			latest = t
+		}
+	}
+
+	actor := username
+	if session[0].Actor != "" {
+		actor = session[0].Actor
+	}
+	title := fmt.Sprintf("%s pushed %s across %d repositories", actor, countNoun(count, "commit", "commits"), len(repos))
+	if conventional {
+		if summary := conventionalSummary(allCommits); summary != "" {
+			title += fmt.Sprintf(" (%s)", summary)
+		}
+	}
+
+	// Create HTML description with a section of commit details for each push
+	var htmlParts []string
+	htmlParts = append(htmlParts, "<div>")
+	for _, push := range session {
+		htmlParts = append(htmlParts, fmt.Sprintf(
+			"<h3 style='margin: 16px 0 8px;'>%s/%s (%s)</h3>",
+			html.EscapeString(pushRepoName(push, username)),
+			html.EscapeString(push.Branch),
+			countNoun(commitCount(push), "commit", "commits"),
+		))
+		htmlParts = append(htmlParts, pushDetailsHTML(push, conventional)...)
+	}
+	htmlParts = append(htmlParts, "</div>")
+	htmlContent := strings.Join(htmlParts, "")
+
+	return &gofeed.Item{
+		Title:           title,
+		Description:     htmlContent,
+		Content:         htmlContent,
+		Published:       latest.Format(time.RFC3339),
+		PublishedParsed: &latest,
+		Updated:         latest.Format(time.RFC3339),
+		UpdatedParsed:   &latest,
+		// Keyed on the session's start, so the GUID stays the same as later pushes join it. Once the
+		// earliest push falls out of the input feed's window, the session starts later and gets a new GUID.
+		GUID: fmt.Sprintf("session-%s-%d", actor, earliest.Unix()),
+	}
+}
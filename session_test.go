@@ -0,0 +1,177 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// sessionTestFeed is a GitHub user feed with pushes to three repositories in one morning, and one to
+// another repository that afternoon
+func sessionTestFeed() *gofeed.Feed {
+	push := func(guid, repo, hash, message string, hour, minute int) *gofeed.Item {
+		published := time.Date(2025, 9, 15, hour, minute, 0, 0, time.UTC)
+		return &gofeed.Item{
+			GUID:  guid,
+			Title: "cdzombak pushed " + repo,
+			Link:  "https://github.com/cdzombak/" + repo + "/compare/a0a0a0a0a0..." + hash,
+			Content: `<a class="branch-name" href="/cdzombak/` + repo + `/tree/main">main</a>` +
+				`<code><a href="/cdzombak/` + repo + `/commit/` + hash + `">` + hash[:7] + `</a></code>` +
+				`<div><blockquote>` + message + `</blockquote></div>`,
+			PublishedParsed: &published,
+		}
+	}
+
+	return &gofeed.Feed{
+		Link: "https://github.com/cdzombak",
+		Items: []*gofeed.Item{
+			push("tag:github.com,2008:PushEvent/5", "dotfiles", "5555555555", "Tidy up", 15, 0),
+			push("tag:github.com,2008:PushEvent/4", "billing", "4444444444", "Rename client", 10, 30),
+			push("tag:github.com,2008:PushEvent/3", "accounts", "3333333333", "Rename client", 10, 0),
+			push("tag:github.com,2008:PushEvent/2", "accounts", "2222222222", "Add client", 9, 45),
+			push("tag:github.com,2008:PushEvent/1", "gateway", "1111111111", "Rename client", 9, 0),
+		},
+	}
+}
+
+func TestSessionConsolidation(t *testing.T) {
+	explanation := &Explanation{}
+	pushes := PushIndex{}
+	output := consolidateCommits(sessionTestFeed(), Options{
+		ConsolidatePushes: true,
+		SessionWindow:     time.Hour,
+		Explain:           explanation,
+		Pushes:            pushes,
+	})
+
+	if len(output.Items) != 2 {
+		t.Fatalf("consolidateCommits() returned %d items, want a push and a session", len(output.Items))
+	}
+	if got := output.Items[0].Title; got != "cdzombak pushed 1 commit to dotfiles/main" {
+		t.Errorf("afternoon item = %q, want the dotfiles push on its own", got)
+	}
+
+	session := output.Items[1]
+	if session.Title != "cdzombak pushed 4 commits across 3 repositories" || session.GUID != "session-cdzombak-1757926800" {
+		t.Errorf("session item = %q %q", session.Title, session.GUID)
+	}
+	if !session.PublishedParsed.Equal(time.Date(2025, 9, 15, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("session item published = %v, want the latest push's time", session.PublishedParsed)
+	}
+
+	// Sections are newest first, each with the repository's compare link
+	sections := []string{
+		"<h3 style='margin: 16px 0 8px;'>billing/main (1 commit)</h3>",
+		"<a href='https://github.com/cdzombak/billing/commit/4444444444'>View all changes</a>",
+		"<h3 style='margin: 16px 0 8px;'>accounts/main (2 commits)</h3>",
+		"<a href='https://github.com/cdzombak/accounts/compare/2222222222^...3333333333'>View all changes</a>",
+		"<h3 style='margin: 16px 0 8px;'>gateway/main (1 commit)</h3>",
+	}
+	position := 0
+	for _, section := range sections {
+		next := strings.Index(session.Content[position:], section)
+		if next == -1 {
+			t.Errorf("session content doesn't contain %q after position %d:\n%s", section, position, session.Content)
+			continue
+		}
+		position += next
+	}
+
+	if len(pushes[session.GUID]) != 3 {
+		t.Errorf("PushIndex has %d pushes for the session, want 3", len(pushes[session.GUID]))
+	}
+	for _, entry := range explanation.Items[1:] {
+		if entry.OutputGUID != session.GUID || !strings.HasSuffix(entry.ConsolidationKey, "/main") {
+			t.Errorf("explanation item %d output = %q, key = %q; want the session with a branch key", entry.Index, entry.OutputGUID, entry.ConsolidationKey)
+		}
+	}
+}
+
+func TestSessionConsolidationDisabled(t *testing.T) {
+	output := consolidateCommits(sessionTestFeed(), Options{ConsolidatePushes: true})
+	if len(output.Items) != 4 {
+		t.Errorf("consolidateCommits() returned %d items, want one per repository/branch", len(output.Items))
+	}
+}
+
+func TestGroupSessions(t *testing.T) {
+	at := func(hour int) *time.Time {
+		t := time.Date(2025, 9, 15, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	pushes := []*BranchActivity{
+		{Repo: "a", Branch: "main", LatestTime: at(1)},
+		{Repo: "a", Branch: "feature", LatestTime: at(2)},
+		{Repo: "b", Branch: "main", LatestTime: at(6)},
+		{Actor: "dependabot[bot]", Repo: "c", Branch: "main", LatestTime: at(6)},
+	}
+
+	tests := []struct {
+		name     string
+		window   time.Duration
+		expected int
+	}{
+		{"No window", 0, 4},
+		// a's pushes are one repository, so they stay separate
+		{"Short window", 2 * time.Hour, 4},
+		// Other actors' pushes never join a session
+		{"Long window", 5 * time.Hour, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupSessions(pushes, tt.window, "cdzombak"); len(got) != tt.expected {
+				t.Errorf("groupSessions() = %d sessions, want %d", len(got), tt.expected)
+			}
+		})
+	}
+}
+
+func TestCreateSessionItemEscapesHeadings(t *testing.T) {
+	published := time.Date(2025, 9, 15, 1, 0, 0, 0, time.UTC)
+	session := []*BranchActivity{
+		{Repo: "dotfiles", Branch: "cdz/<b>&co", Commits: []Commit{{Hash: "8e9b024", Message: "Fix"}}, LatestTime: &published},
+		{Owner: "mmcdole", Repo: "gofeed", Branch: "main", Commits: []Commit{{Hash: "b19a1b6", Message: "Fix"}}, LatestTime: &published},
+	}
+
+	item := createSessionItem(session, "cdzombak", false)
+	if !strings.Contains(item.Content, "<h3 style='margin: 16px 0 8px;'>dotfiles/cdz/&lt;b&gt;&amp;co (1 commit)</h3>") {
+		t.Errorf("session content = %v, want the branch escaped", item.Content)
+	}
+}
+
+func TestPushesRepo(t *testing.T) {
+	tests := []struct {
+		pushes   []*BranchActivity
+		expected string
+	}{
+		{[]*BranchActivity{{Repo: "dotfiles"}}, "cdzombak/dotfiles"},
+		{[]*BranchActivity{{Repo: "dotfiles", Branch: "main"}, {Repo: "dotfiles", Branch: "wip"}}, "cdzombak/dotfiles"},
+		{[]*BranchActivity{{Repo: "dotfiles"}, {Owner: "mmcdole", Repo: "gofeed"}}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := pushesRepo(tt.pushes, "cdzombak"); got != tt.expected {
+			t.Errorf("pushesRepo() = %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestDigestSession(t *testing.T) {
+	output := consolidateCommits(sessionTestFeed(), Options{
+		ConsolidatePushes: true,
+		SessionWindow:     time.Hour,
+		Digest:            digestDaily,
+		DigestLocation:    time.UTC,
+	})
+
+	want := "Activity for Monday, September 15, 2025: 5 commits in 4 repositories"
+	if len(output.Items) != 1 || output.Items[0].Title != want {
+		t.Fatalf("consolidateCommits() = %v, want one digest %q", output.Items, want)
+	}
+	if !strings.Contains(output.Items[0].Content, "<h3 style='margin: 16px 0 8px;'>Multiple repositories</h3>") {
+		t.Errorf("digest doesn't have a section for the session:\n%s", output.Items[0].Content)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// exitStale is the exit status when the upstream feed couldn't be fetched and the cached feed was served
+// instead (EX_TEMPFAIL from sysexits.h), letting monitoring tell a degraded run from a hard failure
+const exitStale = 75
+
+// saveStaleCache stores the consolidated feed at path so it can be served if a later fetch fails.
+// The file is replaced atomically so a failed write never clobbers the previous good copy.
+func saveStaleCache(path string, feed *gofeed.Feed) error {
+	data, err := json.Marshal(feed)
+	if err != nil {
+		return fmt.Errorf("encoding feed: %w", err)
+	}
+
+	return writeFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// writeFileAtomic replaces the file at path with what write writes, via a temporary file in the same
+// directory, so readers never see a partial file and a failed write leaves the previous file in place.
+// The file is world-readable.
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	// CreateTemp makes the file private; outputs are usually served by a web server
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// loadStaleCache reads the feed saved by saveStaleCache, returning it along with when it was saved
+func loadStaleCache(path string) (*gofeed.Feed, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var feed gofeed.Feed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, time.Time{}, fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	return &feed, info.ModTime(), nil
+}
+
+// createStaleItem creates a synthetic item noting the feed hasn't been updated since savedAt.
+// Its GUID depends only on savedAt, so repeated failed runs during one outage produce a single item.
+func createStaleItem(feed *gofeed.Feed, savedAt, now time.Time) *gofeed.Item {
+	title := "Feed temporarily stale"
+	htmlContent := `<div style='margin-bottom: 12px;'>`
+	htmlContent += fmt.Sprintf(`GitHub activity couldn't be fetched; this feed was last updated %s.`, savedAt.UTC().Format(time.RFC1123))
+	htmlContent += `</div>`
+
+	return &gofeed.Item{
+		Title:           title,
+		Description:     htmlContent,
+		Content:         htmlContent,
+		Link:            feed.Link,
+		Published:       now.Format(time.RFC3339),
+		PublishedParsed: &now,
+		Updated:         now.Format(time.RFC3339),
+		UpdatedParsed:   &now,
+		GUID:            fmt.Sprintf("stale-%d", savedAt.Unix()),
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestStaleCacheRoundTrip(t *testing.T) {
+	publishedTime, _ := time.Parse(time.RFC3339, "2025-09-15T01:28:02Z")
+	path := filepath.Join(t.TempDir(), "ghfeed-cache.json")
+
+	inputFeed := &gofeed.Feed{
+		Title: "cdzombak's Activity",
+		Link:  "https://github.com/cdzombak",
+		Items: []*gofeed.Item{
+			{
+				Title:           "cdzombak pushed 2 commits to dotfiles/master",
+				Content:         "<div>commits</div>",
+				Link:            "https://github.com/cdzombak/dotfiles/compare/abc^...def",
+				PublishedParsed: &publishedTime,
+				GUID:            "consolidated-dotfiles-master-1757899682",
+			},
+		},
+	}
+
+	if err := saveStaleCache(path, inputFeed); err != nil {
+		t.Fatalf("saveStaleCache() error = %v", err)
+	}
+
+	feed, savedAt, err := loadStaleCache(path)
+	if err != nil {
+		t.Fatalf("loadStaleCache() error = %v", err)
+	}
+	if savedAt.IsZero() {
+		t.Errorf("loadStaleCache() savedAt is zero")
+	}
+	if feed.Title != inputFeed.Title {
+		t.Errorf("loadStaleCache().Title = %v, want %v", feed.Title, inputFeed.Title)
+	}
+	if len(feed.Items) != 1 {
+		t.Fatalf("loadStaleCache() items count = %d, want 1", len(feed.Items))
+	}
+	if feed.Items[0].GUID != inputFeed.Items[0].GUID {
+		t.Errorf("loadStaleCache().Items[0].GUID = %v, want %v", feed.Items[0].GUID, inputFeed.Items[0].GUID)
+	}
+	if feed.Items[0].PublishedParsed == nil || !feed.Items[0].PublishedParsed.Equal(publishedTime) {
+		t.Errorf("loadStaleCache().Items[0].PublishedParsed = %v, want %v", feed.Items[0].PublishedParsed, publishedTime)
+	}
+
+	// Overwriting should leave no temporary files behind
+	if err := saveStaleCache(path, inputFeed); err != nil {
+		t.Fatalf("saveStaleCache() overwrite error = %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("cache directory has %d entries, want 1", len(entries))
+	}
+}
+
+func TestLoadStaleCacheMissing(t *testing.T) {
+	if _, _, err := loadStaleCache(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("loadStaleCache() of missing file should fail")
+	}
+}
+
+func TestCreateStaleItem(t *testing.T) {
+	savedAt := time.Date(2025, 9, 15, 1, 28, 2, 0, time.UTC)
+	feed := &gofeed.Feed{Link: "https://github.com/cdzombak"}
+
+	first := createStaleItem(feed, savedAt, savedAt.Add(time.Hour))
+	second := createStaleItem(feed, savedAt, savedAt.Add(2*time.Hour))
+
+	if first.GUID != second.GUID {
+		t.Errorf("createStaleItem() GUIDs differ within one outage: %v, %v", first.GUID, second.GUID)
+	}
+	if !strings.Contains(first.Title, "stale") {
+		t.Errorf("createStaleItem().Title = %v, want mention of stale", first.Title)
+	}
+	if !strings.Contains(first.Content, "15 Sep 2025") {
+		t.Errorf("createStaleItem().Content should mention last update, got %v", first.Content)
+	}
+	if first.Link != feed.Link {
+		t.Errorf("createStaleItem().Link = %v, want %v", first.Link, feed.Link)
+	}
+}
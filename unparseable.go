@@ -0,0 +1,135 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Ways to handle items that fell back to degraded parsing, for -unparseable
+const (
+	unparseableIgnore = "ignore"
+	unparseableWarn   = "warn"
+	unparseableFail   = "fail"
+)
+
+// exitUnparseable is the exit status when -unparseable fail finds degraded items (EX_DATAERR from sysexits.h)
+const exitUnparseable = 65
+
+// Fallbacks recorded on activities whose source items weren't fully understood, usually because GitHub
+// changed its markup
+const (
+	fallbackNoRepo       = "push without a repository link"
+	fallbackNoBranch     = "push without a branch link (assumed master)"
+	fallbackCommitLinks  = "commits without messages"
+	fallbackNoCommits    = "push without commits"
+	fallbackUnrecognized = "unrecognized activity"
+)
+
+// Samples kept per fallback, and how much of each sample's content is kept
+const (
+	maxFallbackSamples   = 3
+	fallbackSampleLength = 500
+)
+
+// ParseWarnings counts the items that fell back to degraded parsing, keeping samples of each
+type ParseWarnings struct {
+	Fallbacks map[string]*FallbackWarning
+	// Items is how many items fell back at least one way
+	Items int
+}
+
+// FallbackWarning is the items that fell back one way
+type FallbackWarning struct {
+	Fallback string
+	Count    int
+	Samples  []FallbackSample
+}
+
+// FallbackSample is an input item that fell back, for filing a parser bug
+type FallbackSample struct {
+	GUID    string
+	Title   string
+	Content string
+}
+
+// activityFallbacks returns the fallbacks used to parse an activity's source items
+func activityFallbacks(activity Activity) []string {
+	if activity.Push != nil {
+		return activity.Push.Fallbacks
+	}
+	return activity.Fallbacks
+}
+
+// record counts the fallbacks used by activities. Like ParseWarnings' other methods, it does nothing on
+// a nil ParseWarnings.
+func (w *ParseWarnings) record(activities []Activity) {
+	if w == nil {
+		return
+	}
+	if w.Fallbacks == nil {
+		w.Fallbacks = make(map[string]*FallbackWarning)
+	}
+
+	for _, activity := range activities {
+		if len(activityFallbacks(activity)) > 0 {
+			w.Items += max(1, len(activitySources(activity)))
+		}
+		for _, fallback := range activityFallbacks(activity) {
+			warning := w.Fallbacks[fallback]
+			if warning == nil {
+				warning = &FallbackWarning{Fallback: fallback}
+				w.Fallbacks[fallback] = warning
+			}
+			warning.Count++
+			for _, source := range activitySources(activity) {
+				if len(warning.Samples) < maxFallbackSamples {
+					warning.Samples = append(warning.Samples, fallbackSample(source))
+				}
+			}
+		}
+	}
+}
+
+// fallbackSample keeps an item's identity and the start of its content
+func fallbackSample(item *gofeed.Item) FallbackSample {
+	content := strings.TrimSpace(itemBody(item))
+	if len(content) > fallbackSampleLength {
+		content = strings.ToValidUTF8(content[:fallbackSampleLength], "") + "…"
+	}
+	return FallbackSample{GUID: item.GUID, Title: item.Title, Content: content}
+}
+
+// Total returns how many items fell back, counting an item once per fallback
+func (w *ParseWarnings) Total() int {
+	if w == nil {
+		return 0
+	}
+	total := 0
+	for _, warning := range w.Fallbacks {
+		total += warning.Count
+	}
+	return total
+}
+
+// log reports each fallback's count and samples as warnings, in a stable order
+func (w *ParseWarnings) log() {
+	if w == nil {
+		return
+	}
+
+	var fallbacks []string
+	for fallback := range w.Fallbacks {
+		fallbacks = append(fallbacks, fallback)
+	}
+	sort.Strings(fallbacks)
+
+	for _, fallback := range fallbacks {
+		warning := w.Fallbacks[fallback]
+		logger.Warn("items fell back to degraded parsing", "fallback", fallback, "count", warning.Count)
+		for _, sample := range warning.Samples {
+			logger.Warn("degraded item", "fallback", fallback, "guid", sample.GUID, "title", sample.Title, "content", sample.Content)
+		}
+	}
+}
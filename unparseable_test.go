@@ -0,0 +1,195 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestParseWarnings(t *testing.T) {
+	published := time.Date(2025, 9, 15, 1, 28, 2, 0, time.UTC)
+	feed := &gofeed.Feed{
+		Link: "https://github.com/cdzombak",
+		Items: []*gofeed.Item{
+			{
+				GUID:            "tag:github.com,2008:PushEvent/1",
+				Title:           "cdzombak pushed dotfiles",
+				Link:            "https://github.com/cdzombak/dotfiles/compare/b19a1b604e...8e9b024bed",
+				Content:         `<a class="branch-name" href="/cdzombak/dotfiles/tree/main">main</a><code><a href="/cdzombak/dotfiles/commit/8e9b024bed">8e9b024</a></code><div><blockquote>Fix zsh prompt</blockquote></div>`,
+				PublishedParsed: &published,
+			},
+			{
+				GUID:            "tag:github.com,2008:PushEvent/2",
+				Title:           "cdzombak pushed ghfeed",
+				Link:            "https://github.com/cdzombak/ghfeed/compare/a...b",
+				Content:         `<span class="new-markup">main</span><a href="/cdzombak/ghfeed/commit/c0ffee1">c0ffee1</a>`,
+				PublishedParsed: &published,
+			},
+			{
+				GUID:            "tag:github.com,2008:PushEvent/3",
+				Title:           "cdzombak pushed to a deleted repository",
+				Link:            "https://github.com/someone-else",
+				PublishedParsed: &published,
+			},
+			{
+				GUID:            "tag:github.com,2008:SponsorshipEvent/1",
+				Title:           "cdzombak started sponsoring mmcdole",
+				Link:            "https://github.com/mmcdole",
+				Content:         strings.Repeat("x", 2*fallbackSampleLength),
+				PublishedParsed: &published,
+			},
+			{
+				GUID:            "tag:github.com,2008:ForkEvent/1",
+				Title:           "cdzombak forked cdzombak/gofeed from mmcdole/gofeed",
+				Link:            "https://github.com/cdzombak/gofeed",
+				PublishedParsed: &published,
+			},
+		},
+	}
+
+	warnings := &ParseWarnings{}
+	consolidateCommits(feed, Options{ConsolidatePushes: true, Warnings: warnings})
+
+	expected := map[string]string{
+		fallbackNoBranch:     "tag:github.com,2008:PushEvent/2",
+		fallbackCommitLinks:  "tag:github.com,2008:PushEvent/2",
+		fallbackNoRepo:       "tag:github.com,2008:PushEvent/3",
+		fallbackUnrecognized: "tag:github.com,2008:SponsorshipEvent/1",
+	}
+	if len(warnings.Fallbacks) != len(expected) {
+		t.Errorf("ParseWarnings recorded %d fallbacks, want %d", len(warnings.Fallbacks), len(expected))
+	}
+	for fallback, guid := range expected {
+		warning := warnings.Fallbacks[fallback]
+		if warning == nil {
+			t.Errorf("ParseWarnings has no %q fallback", fallback)
+			continue
+		}
+		if warning.Count != 1 || len(warning.Samples) != 1 || warning.Samples[0].GUID != guid {
+			t.Errorf("ParseWarnings %q = %+v, want one sample of %s", fallback, warning, guid)
+		}
+	}
+	if got := warnings.Total(); got != 4 {
+		t.Errorf("ParseWarnings.Total() = %d, want 4", got)
+	}
+	// PushEvent/2 fell back two ways
+	if warnings.Items != 3 {
+		t.Errorf("ParseWarnings.Items = %d, want 3", warnings.Items)
+	}
+
+	sample := warnings.Fallbacks[fallbackUnrecognized].Samples[0]
+	if want := strings.Repeat("x", fallbackSampleLength) + "…"; sample.Content != want {
+		t.Errorf("sample content is %d bytes, want truncated to %d", len(sample.Content), fallbackSampleLength)
+	}
+}
+
+func TestParseWarningsOrdinaryPushes(t *testing.T) {
+	published := time.Date(2025, 9, 15, 1, 28, 2, 0, time.UTC)
+	feed := &gofeed.Feed{
+		Link: "https://github.com/cdzombak",
+		Items: []*gofeed.Item{
+			{
+				GUID:            "tag:github.com,2008:PushEvent/1",
+				Title:           "cdzombak pushed dotfiles",
+				Link:            "https://github.com/cdzombak/dotfiles/compare/b19a1b604e...8e9b024bed",
+				Content:         `<a class="branch-name" href="/cdzombak/dotfiles/tree/master">master</a><code><a href="/cdzombak/dotfiles/commit/8e9b024bed">8e9b024</a></code><div><blockquote>Fix zsh prompt</blockquote></div>`,
+				PublishedParsed: &published,
+			},
+			{
+				GUID:            "tag:github.com,2008:CreateEvent/1",
+				Title:           "cdzombak created branch cdz/events in cdzombak/ghfeed",
+				Link:            "https://github.com/cdzombak/ghfeed/tree/cdz/events",
+				PublishedParsed: &published,
+			},
+		},
+	}
+
+	warnings := &ParseWarnings{}
+	consolidateCommits(feed, Options{ConsolidatePushes: true, Warnings: warnings})
+	if got := warnings.Total(); got != 0 {
+		t.Errorf("ParseWarnings.Total() = %d, want 0 for a master push and a branch creation: %+v", got, warnings.Fallbacks)
+	}
+}
+
+func TestParseWarningsSamples(t *testing.T) {
+	var activities []Activity
+	for i := 0; i < 5; i++ {
+		item := &gofeed.Item{GUID: strings.Repeat("a", i+1), Title: "cdzombak did something"}
+		activities = append(activities, Activity{Item: item, Sources: []*gofeed.Item{item}, Fallbacks: []string{fallbackUnrecognized}})
+	}
+
+	warnings := &ParseWarnings{}
+	warnings.record(activities)
+
+	warning := warnings.Fallbacks[fallbackUnrecognized]
+	if warning.Count != 5 || len(warning.Samples) != maxFallbackSamples {
+		t.Errorf("ParseWarnings = %d items with %d samples, want 5 items with %d samples", warning.Count, len(warning.Samples), maxFallbackSamples)
+	}
+}
+
+func TestParseWarningsLog(t *testing.T) {
+	buf := captureLogs(t, slog.LevelWarn)
+
+	item := &gofeed.Item{GUID: "tag:github.com,2008:SponsorshipEvent/1", Title: "cdzombak started sponsoring mmcdole", Content: "<div>sponsor</div>"}
+	warnings := &ParseWarnings{}
+	warnings.record([]Activity{{Item: item, Sources: []*gofeed.Item{item}, Fallbacks: []string{fallbackUnrecognized}}})
+	warnings.log()
+
+	records := logRecords(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("log() wrote %d records, want 2:\n%s", len(records), buf.String())
+	}
+	if records[0]["fallback"] != fallbackUnrecognized || records[0]["count"] != float64(1) {
+		t.Errorf("log() summary = %v, want count 1 of %q", records[0], fallbackUnrecognized)
+	}
+	if records[1]["guid"] != item.GUID || records[1]["content"] != item.Content {
+		t.Errorf("log() sample = %v, want %s with its content", records[1], item.GUID)
+	}
+
+	// Without warnings requested, nothing is recorded or logged
+	var none *ParseWarnings
+	none.record([]Activity{{Item: item, Fallbacks: []string{fallbackUnrecognized}}})
+	none.log()
+	if none.Total() != 0 || len(logRecords(t, buf)) != 2 {
+		t.Error("nil ParseWarnings recorded or logged warnings")
+	}
+}
+
+// TestUnparseableFailCleanFeed runs ghfeed with -unparseable fail on a feed it fully understands, in a
+// subprocess so its exit status can be checked
+func TestUnparseableFailCleanFeed(t *testing.T) {
+	if feedURL := os.Getenv("GHFEED_TEST_FEED_URL"); feedURL != "" {
+		os.Args = []string{"ghfeed", "-unparseable", "fail", feedURL}
+		main()
+		return
+	}
+
+	fixture, err := os.ReadFile("testdata/activity.atom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestUnparseableFailCleanFeed$")
+	cmd.Env = append(os.Environ(), "GHFEED_TEST_FEED_URL="+server.URL+"/cdzombak.atom")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("ghfeed -unparseable fail error = %v, want exit 0:\n%s", err, stderr.String())
+	}
+	if !strings.Contains(string(output), "cdzombak pushed 1 commit to dotfiles/master") {
+		t.Errorf("ghfeed -unparseable fail output missing the push:\n%s", output)
+	}
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Incoming-webhook payload formats
+const (
+	webhookSlack      = "slack"
+	webhookDiscord    = "discord"
+	webhookMattermost = "mattermost"
+)
+
+// webhookStateRetention is how long sent items are remembered; older items have left the upstream feed
+const webhookStateRetention = 30 * 24 * time.Hour
+
+// maxWebhookCommits is how many commits a push notification lists
+const maxWebhookCommits = 10
+
+// PushIndex records the pushes behind each consolidated output item, by GUID, so notifications can list
+// commits without parsing the item's HTML. Items for sessions across repositories have several.
+type PushIndex map[string][]*BranchActivity
+
+// add records the pushes behind an output item; it does nothing on a nil PushIndex
+func (p PushIndex) add(guid string, pushes ...*BranchActivity) {
+	if p != nil {
+		p[guid] = pushes
+	}
+}
+
+// Webhook posts newly seen feed items to a Slack, Discord, or Mattermost incoming webhook
+type Webhook struct {
+	URL    string
+	Kind   string // slack, discord, or mattermost
+	Client *http.Client
+	// StatePath is the file that records what has been sent, so nothing is posted twice
+	StatePath string
+}
+
+// webhookState is what a Webhook has sent: item GUIDs and commit links, with when each was sent
+type webhookState struct {
+	Sent map[string]time.Time `json:"sent"`
+}
+
+// Notification is a new feed item to post, with the structured details payloads are built from
+type Notification struct {
+	Title string
+	Link  string
+	Time  time.Time
+	// Commits are a push's newly seen commits, newest first
+	Commits []Commit
+	// PullRequest is set for pull request items
+	PullRequest *PullRequestFields
+}
+
+// PullRequestFields are the details of a pull request item
+type PullRequestFields struct {
+	Actor  string
+	Action string
+	Number string
+	Repo   string
+	Title  string
+}
+
+// parsePullRequestTitle parses the title createPullRequestItem gives pull request items, like
+// "cdzombak opened PR #264 in mmcdole/gofeed: Add JSON output"
+func parsePullRequestTitle(title string) (*PullRequestFields, bool) {
+	prRegex := regexp.MustCompile(`^(\S+) (\S+) PR #(\d+) in (\S+?)(?:: (.*))?$`)
+	matches := prRegex.FindStringSubmatch(title)
+	if matches == nil {
+		return nil, false
+	}
+	return &PullRequestFields{Actor: matches[1], Action: matches[2], Number: matches[3], Repo: matches[4], Title: matches[5]}, true
+}
+
+// Notify posts the feed's items that haven't been sent before, oldest first. A push that was posted
+// before is posted again only with its new commits. The first run, with no state file, records the feed's
+// items as sent without posting them, so a new webhook isn't flooded with old activity.
+// If a post fails, what was sent so far is still recorded.
+func (w *Webhook) Notify(feed *gofeed.Feed, pushes PushIndex, now time.Time) error {
+	state, err := loadWebhookState(w.StatePath)
+	baseline := errors.Is(err, fs.ErrNotExist)
+	if err != nil && !baseline {
+		return err
+	}
+
+	items := slices.Clone(feed.Items)
+	slices.Reverse(items)
+
+	var notifyErr error
+	for _, item := range items {
+		notification, keys := newNotification(item, pushes[item.GUID], state)
+		if notification != nil && !baseline {
+			if notifyErr = w.post(*notification); notifyErr != nil {
+				break
+			}
+			logger.Info("posted item to webhook", "guid", item.GUID, "title", notification.Title)
+		}
+		for _, key := range keys {
+			state.Sent[key] = now
+		}
+	}
+	if baseline {
+		logger.Info("recorded existing items without posting them", "items", len(items), "state", w.StatePath)
+	}
+
+	for key, sentAt := range state.Sent {
+		if now.Sub(sentAt) > webhookStateRetention {
+			delete(state.Sent, key)
+		}
+	}
+	if err := saveWebhookState(w.StatePath, state); err != nil {
+		return errors.Join(notifyErr, fmt.Errorf("saving webhook state: %w", err))
+	}
+	return notifyErr
+}
+
+// newNotification returns the notification for an item, or nil if it has been sent, along with the state
+// keys to record once it's posted
+func newNotification(item *gofeed.Item, pushes []*BranchActivity, state *webhookState) (*Notification, []string) {
+	notification := &Notification{Title: item.Title, Link: item.Link}
+	if item.PublishedParsed != nil {
+		notification.Time = *item.PublishedParsed
+	}
+
+	if len(pushes) == 0 {
+		if _, sent := state.Sent[item.GUID]; sent {
+			return nil, nil
+		}
+		if pr, ok := parsePullRequestTitle(item.Title); ok {
+			notification.PullRequest = pr
+		}
+		return notification, []string{item.GUID}
+	}
+
+	// A push item's GUID changes as more pushes are consolidated into it, so track its commits instead.
+	// Commits from the git source may have no link, so they're tracked by hash.
+	keys := []string{item.GUID}
+	for _, push := range pushes {
+		for _, commit := range push.Commits {
+			key := "commit:" + commit.Hash
+			if _, sent := state.Sent[key]; !sent {
+				notification.Commits = append(notification.Commits, commit)
+			}
+			keys = append(keys, key)
+		}
+	}
+	if len(notification.Commits) == 0 {
+		return nil, keys
+	}
+	if len(notification.Commits) < len(keys)-1 {
+		notification.Title = fmt.Sprintf("%s (%d new)", item.Title, len(notification.Commits))
+	}
+	return notification, keys
+}
+
+// post sends a notification to the webhook
+func (w *Webhook) post(notification Notification) error {
+	var payload any
+	switch w.Kind {
+	case webhookSlack:
+		payload = slackPayload(notification)
+	case webhookDiscord:
+		payload = discordPayload(notification)
+	case webhookMattermost:
+		payload = mattermostPayload(notification)
+	default:
+		return fmt.Errorf("unsupported webhook type: %s", w.Kind)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultFetchTimeout}
+	}
+	resp, err := client.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The webhook URL is a credential, so keep it out of errors
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("posting to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("posting to webhook: %s", resp.Status)
+	}
+	return nil
+}
+
+// notificationCommitLines formats a notification's commits as Markdown lines, linking each hash with link
+func notificationCommitLines(notification Notification, link func(text, url string) string, escape func(string) string) []string {
+	var lines []string
+	for i, commit := range notification.Commits {
+		if i == maxWebhookCommits {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(notification.Commits)-maxWebhookCommits))
+			break
+		}
+		hash := "`" + commit.Hash + "`"
+		if commit.Link != "" {
+			hash = link(hash, commit.Link)
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", hash, escape(htmlText(commit.Message))))
+	}
+	return lines
+}
+
+// slackEscape escapes text for Slack's mrkdwn
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// slackPayload builds a Slack incoming-webhook message with blocks
+func slackPayload(notification Notification) map[string]any {
+	link := func(text, href string) string { return fmt.Sprintf("<%s|%s>", href, text) }
+
+	headline := "*" + slackEscape(notification.Title) + "*"
+	if notification.Link != "" {
+		headline = "*" + link(slackEscape(notification.Title), notification.Link) + "*"
+	}
+	blocks := []map[string]any{
+		{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": headline}},
+	}
+	if lines := notificationCommitLines(notification, link, slackEscape); len(lines) > 0 {
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": strings.Join(lines, "\n")},
+		})
+	}
+	if pr := notification.PullRequest; pr != nil {
+		blocks = append(blocks, map[string]any{
+			"type":     "context",
+			"elements": []map[string]any{{"type": "mrkdwn", "text": slackEscape(fmt.Sprintf("%s · #%s · %s", pr.Repo, pr.Number, pr.Action))}},
+		})
+	}
+
+	return map[string]any{"text": notification.Title, "blocks": blocks}
+}
+
+// markdownLink formats a Markdown link, as Discord and Mattermost use
+func markdownLink(text, href string) string {
+	return fmt.Sprintf("[%s](%s)", text, href)
+}
+
+// noEscape leaves text as is
+func noEscape(text string) string {
+	return text
+}
+
+// discordPayload builds a Discord webhook message with an embed
+func discordPayload(notification Notification) map[string]any {
+	embed := map[string]any{"title": truncateRunes(notification.Title, 256)}
+	if notification.Link != "" {
+		embed["url"] = notification.Link
+	}
+	if !notification.Time.IsZero() {
+		embed["timestamp"] = notification.Time.UTC().Format(time.RFC3339)
+	}
+	if lines := notificationCommitLines(notification, markdownLink, noEscape); len(lines) > 0 {
+		embed["description"] = truncateRunes(strings.Join(lines, "\n"), 4096)
+	}
+	if pr := notification.PullRequest; pr != nil {
+		embed["fields"] = []map[string]any{
+			{"name": "Repository", "value": pr.Repo, "inline": true},
+			{"name": "Pull request", "value": "#" + pr.Number, "inline": true},
+		}
+	}
+
+	return map[string]any{"embeds": []map[string]any{embed}}
+}
+
+// mattermostPayload builds a Mattermost incoming-webhook message with an attachment
+func mattermostPayload(notification Notification) map[string]any {
+	attachment := map[string]any{
+		"fallback": notification.Title,
+		"title":    notification.Title,
+	}
+	if notification.Link != "" {
+		attachment["title_link"] = notification.Link
+	}
+	if lines := notificationCommitLines(notification, markdownLink, noEscape); len(lines) > 0 {
+		attachment["text"] = strings.Join(lines, "\n")
+	}
+	if pr := notification.PullRequest; pr != nil {
+		attachment["fields"] = []map[string]any{
+			{"title": "Repository", "value": pr.Repo, "short": true},
+			{"title": "Pull request", "value": "#" + pr.Number, "short": true},
+		}
+	}
+
+	return map[string]any{"attachments": []map[string]any{attachment}}
+}
+
+// truncateRunes shortens text to at most n runes, ending it with an ellipsis if it was cut
+func truncateRunes(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// loadWebhookState reads what a webhook has sent; the error wraps fs.ErrNotExist if nothing has been sent
+func loadWebhookState(path string) (*webhookState, error) {
+	state := &webhookState{Sent: make(map[string]time.Time)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return state, fmt.Errorf("decoding webhook state %s: %w", path, err)
+	}
+	if state.Sent == nil {
+		state.Sent = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+// saveWebhookState records what a webhook has sent
+func saveWebhookState(path string, state *webhookState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// webhookReceiver is an incoming webhook that records the payloads posted to it
+type webhookReceiver struct {
+	mu       sync.Mutex
+	payloads []map[string]any
+	status   int
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil || req.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.status != 0 {
+		w.WriteHeader(r.status)
+		return
+	}
+	r.payloads = append(r.payloads, payload)
+}
+
+// texts returns the fallback text of each Slack payload posted
+func (r *webhookReceiver) texts() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var texts []string
+	for _, payload := range r.payloads {
+		texts = append(texts, payload["text"].(string))
+	}
+	return texts
+}
+
+// notifyFeed consolidates the items of explainTestFeed at the given indexes and notifies the webhook
+func notifyFeed(t *testing.T, webhook *Webhook, indexes ...int) error {
+	t.Helper()
+	full := explainTestFeed()
+	feed := &gofeed.Feed{Link: full.Link}
+	for _, i := range indexes {
+		feed.Items = append(feed.Items, full.Items[i])
+	}
+
+	pushes := PushIndex{}
+	consolidated := consolidateCommits(feed, Options{ConsolidatePushes: true, Pushes: pushes})
+	return webhook.Notify(consolidated, pushes, time.Date(2025, 9, 15, 12, 0, 0, 0, time.UTC))
+}
+
+func TestWebhookNotify(t *testing.T) {
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	webhook := &Webhook{URL: server.URL, Kind: webhookSlack, StatePath: filepath.Join(t.TempDir(), "state.json")}
+
+	// The first run records the feed without posting it
+	if err := notifyFeed(t, webhook, 2, 3); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if texts := receiver.texts(); len(texts) != 0 {
+		t.Errorf("first Notify() posted %q, want nothing", texts)
+	}
+
+	// Later runs post new items, oldest first, and only a push's new commits
+	if err := notifyFeed(t, webhook, 0, 1, 2, 3); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	expected := []string{
+		"cdzombak opened PR #264 in mmcdole/gofeed",
+		"cdzombak pushed 2 commits to dotfiles/main (1 new)",
+	}
+	if texts := receiver.texts(); !reflect.DeepEqual(texts, expected) {
+		t.Errorf("Notify() posted %q, want %q", texts, expected)
+	}
+	commits := receiver.payloads[1]["blocks"].([]any)[1].(map[string]any)["text"].(map[string]any)["text"].(string)
+	if !strings.Contains(commits, "8e9b024") || strings.Contains(commits, "b19a1b6") {
+		t.Errorf("Notify() listed commits %q, want only 8e9b024", commits)
+	}
+
+	if err := notifyFeed(t, webhook, 0, 1, 2, 3); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if texts := receiver.texts(); len(texts) != len(expected) {
+		t.Errorf("Notify() posted %q again", texts[len(expected):])
+	}
+}
+
+func TestWebhookNotifyFailure(t *testing.T) {
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	webhook := &Webhook{URL: server.URL + "/secret", Kind: webhookSlack, StatePath: filepath.Join(t.TempDir(), "state.json")}
+
+	if err := notifyFeed(t, webhook, 3); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	receiver.status = http.StatusInternalServerError
+	err := notifyFeed(t, webhook, 1, 3)
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("Notify() error = %v, want an error without the webhook URL", err)
+	}
+
+	// What wasn't sent is retried
+	receiver.status = 0
+	if err := notifyFeed(t, webhook, 1, 3); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if texts := receiver.texts(); len(texts) != 1 {
+		t.Errorf("Notify() posted %q after a failure, want the pull request", texts)
+	}
+}
+
+func TestWebhookStatePruned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	now := time.Date(2025, 9, 15, 12, 0, 0, 0, time.UTC)
+	old := &webhookState{Sent: map[string]time.Time{
+		"old":    now.Add(-webhookStateRetention - time.Hour),
+		"recent": now.Add(-time.Hour),
+	}}
+	if err := saveWebhookState(path, old); err != nil {
+		t.Fatal(err)
+	}
+
+	webhook := &Webhook{URL: "http://127.0.0.1:0", Kind: webhookSlack, StatePath: path}
+	if err := webhook.Notify(&gofeed.Feed{}, nil, now); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	state, err := loadWebhookState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state.Sent["old"]; ok {
+		t.Errorf("Notify() kept an entry older than the retention period")
+	}
+	if _, ok := state.Sent["recent"]; !ok {
+		t.Errorf("Notify() pruned a recent entry")
+	}
+}
+
+func TestLoadWebhookStateMissing(t *testing.T) {
+	_, err := loadWebhookState(filepath.Join(t.TempDir(), "state.json"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("loadWebhookState() error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestParsePullRequestTitle(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected *PullRequestFields
+	}{
+		{
+			"cdzombak opened PR #264 in mmcdole/gofeed: Add JSON output",
+			&PullRequestFields{Actor: "cdzombak", Action: "opened", Number: "264", Repo: "mmcdole/gofeed", Title: "Add JSON output"},
+		},
+		{
+			"cdzombak merged PR #12 in cdzombak/ghfeed",
+			&PullRequestFields{Actor: "cdzombak", Action: "merged", Number: "12", Repo: "cdzombak/ghfeed"},
+		},
+		{"cdzombak pushed 2 commits to dotfiles/main", nil},
+	}
+
+	for _, tt := range tests {
+		got, ok := parsePullRequestTitle(tt.title)
+		if ok != (tt.expected != nil) || !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("parsePullRequestTitle(%q) = %+v, want %+v", tt.title, got, tt.expected)
+		}
+	}
+}
+
+func TestWebhookPayloads(t *testing.T) {
+	published := time.Date(2025, 9, 15, 1, 28, 2, 0, time.UTC)
+	push := Notification{
+		Title: "cdzombak pushed 2 commits to dotfiles/main",
+		Link:  "https://github.com/cdzombak/dotfiles/compare/a...b",
+		Time:  published,
+		Commits: []Commit{
+			{Hash: "8e9b024", Link: "https://github.com/cdzombak/dotfiles/commit/8e9b024", Message: "Fix &lt;prompt&gt; &amp; colors"},
+		},
+	}
+	pr := Notification{
+		Title:       "cdzombak opened PR #264 in mmcdole/gofeed: Add JSON output",
+		Link:        "https://github.com/mmcdole/gofeed/pull/264",
+		PullRequest: &PullRequestFields{Actor: "cdzombak", Action: "opened", Number: "264", Repo: "mmcdole/gofeed", Title: "Add JSON output"},
+	}
+
+	tests := []struct {
+		name     string
+		payload  map[string]any
+		expected string
+	}{
+		{
+			"Slack push",
+			slackPayload(push),
+			`{"blocks":[{"text":{"text":"*<https://github.com/cdzombak/dotfiles/compare/a...b|cdzombak pushed 2 commits to dotfiles/main>*","type":"mrkdwn"},"type":"section"},{"text":{"text":"<https://github.com/cdzombak/dotfiles/commit/8e9b024|` + "`8e9b024`" + `> Fix &lt;prompt&gt; &amp; colors","type":"mrkdwn"},"type":"section"}],"text":"cdzombak pushed 2 commits to dotfiles/main"}`,
+		},
+		{
+			"Slack pull request",
+			slackPayload(pr),
+			`{"blocks":[{"text":{"text":"*<https://github.com/mmcdole/gofeed/pull/264|cdzombak opened PR #264 in mmcdole/gofeed: Add JSON output>*","type":"mrkdwn"},"type":"section"},{"elements":[{"text":"mmcdole/gofeed · #264 · opened","type":"mrkdwn"}],"type":"context"}],"text":"cdzombak opened PR #264 in mmcdole/gofeed: Add JSON output"}`,
+		},
+		{
+			"Discord push",
+			discordPayload(push),
+			`{"embeds":[{"description":"[` + "`8e9b024`" + `](https://github.com/cdzombak/dotfiles/commit/8e9b024) Fix <prompt> & colors","timestamp":"2025-09-15T01:28:02Z","title":"cdzombak pushed 2 commits to dotfiles/main","url":"https://github.com/cdzombak/dotfiles/compare/a...b"}]}`,
+		},
+		{
+			"Discord pull request",
+			discordPayload(pr),
+			`{"embeds":[{"fields":[{"inline":true,"name":"Repository","value":"mmcdole/gofeed"},{"inline":true,"name":"Pull request","value":"#264"}],"title":"cdzombak opened PR #264 in mmcdole/gofeed: Add JSON output","url":"https://github.com/mmcdole/gofeed/pull/264"}]}`,
+		},
+		{
+			"Mattermost push",
+			mattermostPayload(push),
+			`{"attachments":[{"fallback":"cdzombak pushed 2 commits to dotfiles/main","text":"[` + "`8e9b024`" + `](https://github.com/cdzombak/dotfiles/commit/8e9b024) Fix <prompt> & colors","title":"cdzombak pushed 2 commits to dotfiles/main","title_link":"https://github.com/cdzombak/dotfiles/compare/a...b"}]}`,
+		},
+		{
+			"Mattermost pull request",
+			mattermostPayload(pr),
+			`{"attachments":[{"fallback":"cdzombak opened PR #264 in mmcdole/gofeed: Add JSON output","fields":[{"short":true,"title":"Repository","value":"mmcdole/gofeed"},{"short":true,"title":"Pull request","value":"#264"}],"title":"cdzombak opened PR #264 in mmcdole/gofeed: Add JSON output","title_link":"https://github.com/mmcdole/gofeed/pull/264"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got strings.Builder
+			encoder := json.NewEncoder(&got)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(tt.payload); err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(got.String()) != tt.expected {
+				t.Errorf("payload =\n%s\nwant\n%s", got.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestWebhookCommitLimit(t *testing.T) {
+	var notification Notification
+	for i := 0; i < maxWebhookCommits+3; i++ {
+		notification.Commits = append(notification.Commits, Commit{Hash: "abc1234", Link: "https://github.com/a/b/commit/abc1234", Message: "Commit"})
+	}
+
+	lines := notificationCommitLines(notification, markdownLink, noEscape)
+	if len(lines) != maxWebhookCommits+1 || lines[maxWebhookCommits] != "…and 3 more" {
+		t.Errorf("notificationCommitLines() = %q, want %d commits and a count of the rest", lines, maxWebhookCommits)
+	}
+}
+
+func TestNewNotificationUnlinkedCommits(t *testing.T) {
+	// The git source's commits have no links without -link-template
+	push := &BranchActivity{Repo: "ghfeed", Branch: "main", Commits: []Commit{
+		{Hash: "bbbbbbb", Message: "Second"},
+		{Hash: "aaaaaaa", Message: "First"},
+	}}
+	state := &webhookState{Sent: map[string]time.Time{"commit:aaaaaaa": time.Now()}}
+
+	notification, keys := newNotification(&gofeed.Item{GUID: "push", Title: "Chris pushed 2 commits to ghfeed/main"}, []*BranchActivity{push}, state)
+	if notification == nil || len(notification.Commits) != 1 || notification.Commits[0].Hash != "bbbbbbb" {
+		t.Fatalf("newNotification() = %+v, want only the unsent commit", notification)
+	}
+	if len(keys) != 3 {
+		t.Errorf("newNotification() keys = %q, want the GUID and a key per commit", keys)
+	}
+
+	lines := notificationCommitLines(*notification, markdownLink, noEscape)
+	if len(lines) != 1 || lines[0] != "`bbbbbbb` Second" {
+		t.Errorf("notificationCommitLines() = %q, want an unlinked hash", lines)
+	}
+}